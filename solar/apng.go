@@ -0,0 +1,133 @@
+package solar
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"io"
+)
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// apngWriter builds an animated png out of frames encoded by image/png, reusing their IHDR and IDAT chunks
+type apngWriter struct {
+
+	// delay of each frame in seconds is delayNumerator / delayDenominator
+	delayNumerator   uint16
+	delayDenominator uint16
+
+	// IHDR chunk data of the first frame, every frame must match it
+	header []byte
+
+	// compressed image data of each frame
+	frames [][]byte
+}
+
+// addFrame compress frame and hold on to it
+func (writer *apngWriter) addFrame(frame image.Image) error {
+	var encoded bytes.Buffer
+	if err := png.Encode(&encoded, frame); err != nil {
+		return err
+	}
+
+	data := encoded.Bytes()[len(pngSignature):]
+	var imageData []byte
+	for len(data) >= 12 {
+		length := binary.BigEndian.Uint32(data[0:4])
+		chunkType := string(data[4:8])
+		chunkData := data[8 : 8+length]
+
+		switch chunkType {
+		case "IHDR":
+			if writer.header == nil {
+				writer.header = append([]byte{}, chunkData...)
+			} else if !bytes.Equal(writer.header, chunkData) {
+				return errors.New("apng frames must all have the same size and color type")
+			}
+		case "IDAT":
+			imageData = append(imageData, chunkData...)
+		}
+
+		data = data[12+length:]
+	}
+
+	writer.frames = append(writer.frames, imageData)
+	return nil
+}
+
+// write the animation, looping forever
+func (writer *apngWriter) write(w io.Writer) error {
+	if len(writer.frames) == 0 {
+		return errors.New("no frames were recorded")
+	}
+
+	if _, err := w.Write(pngSignature); err != nil {
+		return err
+	}
+	if err := writePngChunk(w, "IHDR", writer.header); err != nil {
+		return err
+	}
+
+	// number of frames then number of plays, 0 is forever
+	animationControl := make([]byte, 8)
+	binary.BigEndian.PutUint32(animationControl[0:4], uint32(len(writer.frames)))
+	if err := writePngChunk(w, "acTL", animationControl); err != nil {
+		return err
+	}
+
+	// fcTL and fdAT chunks share one sequence number counter
+	sequence := uint32(0)
+	for i, imageData := range writer.frames {
+		frameControl := make([]byte, 26)
+		binary.BigEndian.PutUint32(frameControl[0:4], sequence)
+		copy(frameControl[4:12], writer.header[0:8]) // width and height, offset stays 0, 0
+		binary.BigEndian.PutUint16(frameControl[20:22], writer.delayNumerator)
+		binary.BigEndian.PutUint16(frameControl[22:24], writer.delayDenominator)
+		sequence++
+		if err := writePngChunk(w, "fcTL", frameControl); err != nil {
+			return err
+		}
+
+		// the first frame is also the default image shown by viewers without apng support
+		if i == 0 {
+			if err := writePngChunk(w, "IDAT", imageData); err != nil {
+				return err
+			}
+			continue
+		}
+
+		frameData := make([]byte, 4+len(imageData))
+		binary.BigEndian.PutUint32(frameData[0:4], sequence)
+		copy(frameData[4:], imageData)
+		sequence++
+		if err := writePngChunk(w, "fdAT", frameData); err != nil {
+			return err
+		}
+	}
+
+	return writePngChunk(w, "IEND", nil)
+}
+
+// writePngChunk write length, type, data and crc of a single chunk
+func writePngChunk(w io.Writer, chunkType string, data []byte) error {
+	header := make([]byte, 8)
+	binary.BigEndian.PutUint32(header[0:4], uint32(len(data)))
+	copy(header[4:8], chunkType)
+
+	crc := crc32.NewIEEE()
+	crc.Write(header[4:8])
+	crc.Write(data)
+
+	footer := make([]byte, 4)
+	binary.BigEndian.PutUint32(footer, crc.Sum32())
+
+	for _, part := range [][]byte{header, data, footer} {
+		if _, err := w.Write(part); err != nil {
+			return err
+		}
+	}
+	return nil
+}
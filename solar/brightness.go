@@ -0,0 +1,140 @@
+package solar
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// how long a scheduled brightness change takes to fade in
+const scheduleRampSeconds = 30.0
+
+// BrightnessRule sets the brightness from a time of day until the next rule
+type BrightnessRule struct {
+	At DailyTime `json:"at"`
+
+	// percent, 0 is off
+	Level float64 `json:"level"`
+}
+
+// Brightness master brightness applied to every display, fading smoothly to each new level
+// A level set by hand holds until the schedule next changes
+type Brightness struct {
+
+	// brightness being shown, 0 to 1
+	current float64
+
+	// brightness being faded to, 0 to 1
+	target float64
+
+	// change in brightness per second while fading
+	rampRate float64
+
+	// rules that make up the day, empty for no schedule
+	schedule []BrightnessRule
+
+	// where sun events in the schedule are computed
+	location *Location
+
+	// level set by hand, 0 to 1
+	manualLevel float64
+
+	// when the level was set by hand, zero if it follows the schedule
+	manualSince time.Time
+}
+
+// BrightnessState json returned by /api/brightness, levels are percent
+type BrightnessState struct {
+	Level  float64 `json:"level"`
+	Target float64 `json:"target"`
+	Manual bool    `json:"manual"`
+}
+
+// NewBrightness full brightness with no schedule
+func NewBrightness() *Brightness {
+	return &Brightness{current: 1, target: 1, manualLevel: 1}
+}
+
+// SetSchedule follow rules each day, sun events are computed for location
+func (brightness *Brightness) SetSchedule(rules []BrightnessRule, location *Location) error {
+	for _, rule := range rules {
+		if err := rule.At.Validate(); err != nil {
+			return err
+		}
+		if rule.Level < 0 || rule.Level > 100 {
+			return fmt.Errorf("brightness level at %s must be 0 to 100", rule.At)
+		}
+	}
+
+	brightness.schedule = rules
+	brightness.location = location
+	return nil
+}
+
+// Set change to percent by hand, fading over rampSeconds
+func (brightness *Brightness) Set(percent float64, rampSeconds float64, now time.Time) {
+	brightness.manualLevel = math.Max(0, math.Min(100, percent)) / 100
+	brightness.manualSince = now
+	brightness.fadeTo(brightness.manualLevel, rampSeconds)
+}
+
+// Level brightness being shown, 0 to 1
+func (brightness *Brightness) Level() float64 {
+	return brightness.current
+}
+
+// State current levels for the api
+func (brightness *Brightness) State() BrightnessState {
+	return BrightnessState{
+		Level:  math.Round(brightness.current * 100),
+		Target: math.Round(brightness.target * 100),
+		Manual: !brightness.manualSince.IsZero(),
+	}
+}
+
+// Animate follow the schedule and move the fade forward by dt
+func (brightness *Brightness) Animate(dt float64, now time.Time) {
+	desired := brightness.manualLevel
+	if level, since, ok := brightness.scheduledLevel(now); ok {
+		// the schedule takes back over once it changes after the level was set by hand
+		if since.After(brightness.manualSince) {
+			brightness.manualSince = time.Time{}
+			desired = level
+		}
+	}
+
+	if desired != brightness.target {
+		brightness.fadeTo(desired, scheduleRampSeconds)
+	}
+
+	if brightness.current < brightness.target {
+		brightness.current = math.Min(brightness.target, brightness.current+brightness.rampRate*dt)
+	} else if brightness.current > brightness.target {
+		brightness.current = math.Max(brightness.target, brightness.current-brightness.rampRate*dt)
+	}
+}
+
+// fadeTo start fading from the current level to target over rampSeconds
+func (brightness *Brightness) fadeTo(target float64, rampSeconds float64) {
+	brightness.target = target
+	if rampSeconds <= 0 {
+		brightness.current = target
+		return
+	}
+	brightness.rampRate = math.Abs(target-brightness.current) / rampSeconds
+}
+
+// scheduledLevel the level of the most recent rule to start before now, and when it started
+func (brightness *Brightness) scheduledLevel(now time.Time) (level float64, since time.Time, ok bool) {
+	// rules from yesterday are still in effect until the first rule of today
+	for _, date := range []time.Time{now.AddDate(0, 0, -1), now} {
+		for _, rule := range brightness.schedule {
+			at, happens, _ := rule.At.On(date, brightness.location)
+			if !happens || at.After(now) || at.Before(since) {
+				continue
+			}
+			level, since, ok = rule.Level/100, at, true
+		}
+	}
+	return level, since, ok
+}
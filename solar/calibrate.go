@@ -0,0 +1,136 @@
+package solar
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+)
+
+// Calibration replaces the animation with markers so angleOffset and angleDirection can be adjusted live from a web page
+type Calibration struct {
+	solarSystem *System
+
+	// layout file the result is saved to
+	layoutPath string
+
+	// markers on the planet being calibrated
+	markers *DrawCalibration
+}
+
+// calibrationState json returned by /api/calibrate
+type calibrationState struct {
+	Planet         string  `json:"planet"`
+	AngleOffset    float64 `json:"angleOffset"`
+	AngleDirection float64 `json:"angleDirection"`
+	Saved          bool    `json:"saved"`
+}
+
+// NewCalibration start calibrating the Sun and add the calibration page to the webserver
+func NewCalibration(solarSystem *System, layoutPath string) *Calibration {
+	calibration := &Calibration{
+		solarSystem: solarSystem,
+		layoutPath:  layoutPath,
+		markers:     NewCalibrationMarkers(Sun, solarSystem),
+	}
+
+	// the markers stay up no matter what the schedule says
+	solarSystem.SceneSchedule().Hold()
+	solarSystem.ClearDrawables()
+	solarSystem.AddDrawable(calibration.markers)
+
+	http.HandleFunc("/calibrate", calibratePageHandler)
+	http.HandleFunc("/api/calibrate", func(w http.ResponseWriter, r *http.Request) { calibration.apiHandler(w, r) })
+
+	return calibration
+}
+
+// apiHandler select a planet and adjust it, form values:
+// planet=Earth selects the planet, nudge=0.05 adds to its angleOffset, flip=1 reverses angleDirection, save=1 writes the layout file
+func (calibration *Calibration) apiHandler(w http.ResponseWriter, r *http.Request) {
+	calibration.solarSystem.Lock()
+	defer calibration.solarSystem.Unlock()
+
+	if name := r.FormValue("planet"); name != "" {
+		planetIndex, ok := ParsePlanetIndex(name)
+		if !ok {
+			http.Error(w, fmt.Sprintf("unknown planet %q", name), http.StatusBadRequest)
+			return
+		}
+		calibration.markers.planet = planetIndex
+	}
+
+	planet := &calibration.solarSystem.planets[calibration.markers.planet]
+	state := calibrationState{Planet: calibration.markers.planet.String()}
+
+	if r.Method == http.MethodPost {
+		if nudge := r.FormValue("nudge"); nudge != "" {
+			amount, err := strconv.ParseFloat(nudge, 64)
+			if err != nil || math.IsNaN(amount) || math.IsInf(amount, 0) {
+				http.Error(w, "nudge must be a number of radians", http.StatusBadRequest)
+				return
+			}
+			planet.angleOffset = math.Round(angleDifference(planet.angleOffset+amount, 0)*100) / 100
+		}
+
+		if r.FormValue("flip") == "1" {
+			planet.angleDirection = -planet.angleDirection
+		}
+
+		if r.FormValue("save") == "1" {
+			if err := SaveLayout(calibration.layoutPath, calibration.solarSystem); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			log.Print("Saved calibration to ", calibration.layoutPath)
+			state.Saved = true
+		}
+	}
+
+	state.AngleOffset = planet.angleOffset
+	state.AngleDirection = planet.angleDirection
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(state)
+}
+
+// Serve static calibration page
+func calibratePageHandler(w http.ResponseWriter, r *http.Request) {
+	planetOptions := ""
+	for planet := Sun; planet <= Neptune; planet++ {
+		planetOptions += fmt.Sprintf("<option>%v</option>", planet)
+	}
+
+	fmt.Fprintf(w, `
+<html>
+	<head><script type="text/javascript"><!--
+		function send(values)
+		{
+			values.planet = document.getElementById("planet").value;
+			fetch("api/calibrate", {method: "POST", body: new URLSearchParams(values)})
+				.then(function(response) { return response.json(); })
+				.then(function(state) {
+					document.getElementById("state").textContent = "angleOffset " + state.angleOffset + ", angleDirection " + state.angleDirection + (state.saved ? ", saved" : "");
+					document.images["layout"].src = "layout.svg?t=" + Date.now();
+				});
+		}
+		window.onload = function() { send({}); }
+	--></script></head>
+	<body bgcolor="#888888">
+		<p>Green is led 0. Nudge the offset until red is at the top of the planet, then flip the direction if blue is on the left instead of the right.</p>
+		<p>
+			<select id="planet" onchange="send({})">%s</select>
+			<button onclick="send({nudge: -0.1})">-0.1</button>
+			<button onclick="send({nudge: -0.01})">-0.01</button>
+			<button onclick="send({nudge: 0.01})">+0.01</button>
+			<button onclick="send({nudge: 0.1})">+0.1</button>
+			<button onclick="send({flip: 1})">Flip direction</button>
+			<button onclick="send({save: 1})">Save</button>
+			<span id="state"></span>
+		</p>
+		<img id="layout" src="layout.svg" height="910" width="1360"/>
+	</body>
+</html>`, planetOptions)
+}
@@ -0,0 +1,40 @@
+package solar
+
+import (
+	"time"
+)
+
+// SimulationClock the time astronomical effects are drawn for, which can run faster than real time to preview days in seconds
+type SimulationClock struct {
+
+	// simulated time, moved forward each frame
+	now time.Time
+
+	// simulated seconds per real second
+	timescale float64
+}
+
+// NewSimulationClock a clock starting at start that runs at real time
+func NewSimulationClock(start time.Time) *SimulationClock {
+	return &SimulationClock{now: start, timescale: 1}
+}
+
+// Now the simulated time
+func (clock *SimulationClock) Now() time.Time {
+	return clock.now
+}
+
+// SetTimescale run scale simulated seconds per real second
+func (clock *SimulationClock) SetTimescale(scale float64) {
+	clock.timescale = scale
+}
+
+// Timescale simulated seconds per real second
+func (clock *SimulationClock) Timescale() float64 {
+	return clock.timescale
+}
+
+// Animate move the clock forward by dt real seconds
+func (clock *SimulationClock) Animate(dt float64) {
+	clock.now = clock.now.Add(time.Duration(dt * clock.timescale * float64(time.Second)))
+}
@@ -0,0 +1,46 @@
+package solar
+
+// correctColor applies the alpha and gamma correction needed before sending a color to a physical led
+func correctColor(color RGBA) (red, green, blue uint8) {
+	red = gammaCorrectionLookup[(uint32(color.R)*uint32(color.A))>>8]
+	green = gammaCorrectionLookup[(uint32(color.G)*uint32(color.A))>>8]
+	blue = gammaCorrectionLookup[(uint32(color.B)*uint32(color.A))>>8]
+	return
+}
+
+// bit shift of red, green and blue for each order a strip can expect its data in, the first color sent is the highest byte
+var stripTypeShifts = map[string][3]uint{
+	"rgb": {16, 8, 0},
+	"rbg": {16, 0, 8},
+	"grb": {8, 16, 0},
+	"gbr": {0, 16, 8},
+	"brg": {8, 0, 16},
+	"bgr": {0, 8, 16},
+}
+
+// packColor combine red, green and blue into the 24 bit value sent to a strip of stripType
+func packColor(stripType string, red, green, blue uint8) uint32 {
+	shifts := stripTypeShifts[stripType]
+	return uint32(red)<<shifts[0] | uint32(green)<<shifts[1] | uint32(blue)<<shifts[2]
+}
+
+// based on a pull request found at http://forums.adafruit.com/viewtopic.php?f=47&t=26591
+// is basically precomputing x = pow(i / 255, 3.0) * 127
+var gammaCorrectionLookup = [256]uint8{
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 1, 1, 1,
+	1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 2, 2, 2, 2,
+	2, 2, 2, 2, 2, 3, 3, 3, 3, 3, 3, 3, 3, 4, 4, 4,
+	4, 4, 4, 4, 5, 5, 5, 5, 5, 6, 6, 6, 6, 6, 7, 7,
+	7, 7, 7, 8, 8, 8, 8, 9, 9, 9, 9, 10, 10, 10, 10, 11,
+	11, 11, 12, 12, 12, 13, 13, 13, 13, 14, 14, 14, 15, 15, 16, 16,
+	16, 17, 17, 17, 18, 18, 18, 19, 19, 20, 20, 21, 21, 21, 22, 22,
+	23, 23, 24, 24, 24, 25, 25, 26, 26, 27, 27, 28, 28, 29, 29, 30,
+	30, 31, 32, 32, 33, 33, 34, 34, 35, 35, 36, 37, 37, 38, 38, 39,
+	40, 40, 41, 41, 42, 43, 43, 44, 45, 45, 46, 47, 47, 48, 49, 50,
+	50, 51, 52, 52, 53, 54, 55, 55, 56, 57, 58, 58, 59, 60, 61, 62,
+	62, 63, 64, 65, 66, 67, 67, 68, 69, 70, 71, 72, 73, 74, 74, 75,
+	76, 77, 78, 79, 80, 81, 82, 83, 84, 85, 86, 87, 88, 89, 90, 91,
+	92, 93, 94, 95, 96, 97, 98, 99, 100, 101, 102, 104, 105, 106, 107, 108,
+	109, 110, 111, 113, 114, 115, 116, 117, 118, 120, 121, 122, 123, 125, 126, 127,
+}
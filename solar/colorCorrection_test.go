@@ -0,0 +1,30 @@
+package solar
+
+import (
+	"testing"
+)
+
+func TestPackColor(t *testing.T) {
+	tests := map[string]uint32{
+		"rgb": 0x112233,
+		"rbg": 0x113322,
+		"grb": 0x221133,
+		"gbr": 0x223311,
+		"brg": 0x331122,
+		"bgr": 0x332211,
+	}
+
+	if len(tests) != len(stripTypeShifts) {
+		t.Fatalf("%d strip types tested, %d supported", len(tests), len(stripTypeShifts))
+	}
+	for stripType := range stripTypeShifts {
+		want, ok := tests[stripType]
+		if !ok {
+			t.Errorf("%s is not tested", stripType)
+			continue
+		}
+		if got := packColor(stripType, 0x11, 0x22, 0x33); got != want {
+			t.Errorf("%s packs to %06x, want %06x", stripType, got, want)
+		}
+	}
+}
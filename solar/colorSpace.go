@@ -0,0 +1,161 @@
+package solar
+
+import (
+	"math"
+)
+
+// ColorSpace how colors are blended between palette stops
+type ColorSpace int
+
+// Color spaces for blending
+const (
+	// blend each channel directly, can pass through muddy greys
+	BlendRGB ColorSpace = iota
+
+	// blend hue the short way around the color wheel, keeps colors saturated
+	BlendHSV
+
+	// blend in a perceptual space, even steps in brightness and hue
+	BlendOKLab
+)
+
+// blendColors mix from and to by progress, 0 to 1, in space
+func blendColors(from RGBA, to RGBA, progress float64, space ColorSpace) RGBA {
+	alpha := clampByte(float64(from.A) + (float64(to.A)-float64(from.A))*progress)
+
+	switch space {
+	case BlendHSV:
+		h1, s1, v1 := rgbToHSV(from)
+		h2, s2, v2 := rgbToHSV(to)
+
+		// a grey has no hue, take the hue of the other color so it does not swing through the wheel
+		if s1 == 0 {
+			h1 = h2
+		} else if s2 == 0 {
+			h2 = h1
+		}
+		hueChange := math.Mod(h2-h1+540, 360) - 180
+		color := hsvToRGB(math.Mod(h1+hueChange*progress+360, 360), s1+(s2-s1)*progress, v1+(v2-v1)*progress)
+		color.A = alpha
+		return color
+
+	case BlendOKLab:
+		l1, a1, b1 := rgbToOKLab(from)
+		l2, a2, b2 := rgbToOKLab(to)
+		color := okLabToRGB(l1+(l2-l1)*progress, a1+(a2-a1)*progress, b1+(b2-b1)*progress)
+		color.A = alpha
+		return color
+	}
+
+	mix := func(a, b uint8) uint8 {
+		return clampByte(float64(a) + (float64(b)-float64(a))*progress)
+	}
+	return RGBA{mix(from.R, to.R), mix(from.G, to.G), mix(from.B, to.B), alpha}
+}
+
+// clampByte round value into 0 to 255
+func clampByte(value float64) uint8 {
+	return uint8(math.Max(0, math.Min(255, value+0.5)))
+}
+
+// HSV color from hue in degrees, saturation and value 0 to 1, fully opaque
+func HSV(hue float64, saturation float64, value float64) RGBA {
+	return hsvToRGB(math.Mod(math.Mod(hue, 360)+360, 360), saturation, value)
+}
+
+// rgbToHSV hue in degrees, saturation and value 0 to 1
+func rgbToHSV(color RGBA) (hue float64, saturation float64, value float64) {
+	r, g, b := float64(color.R)/255, float64(color.G)/255, float64(color.B)/255
+	max := math.Max(r, math.Max(g, b))
+	min := math.Min(r, math.Min(g, b))
+	delta := max - min
+
+	value = max
+	if max > 0 {
+		saturation = delta / max
+	}
+	if delta == 0 {
+		return 0, saturation, value
+	}
+
+	switch max {
+	case r:
+		hue = math.Mod((g-b)/delta+6, 6)
+	case g:
+		hue = (b-r)/delta + 2
+	default:
+		hue = (r-g)/delta + 4
+	}
+	return hue * 60, saturation, value
+}
+
+// hsvToRGB opaque color from hue in degrees 0 to 360, saturation and value 0 to 1
+func hsvToRGB(hue float64, saturation float64, value float64) RGBA {
+	chroma := value * saturation
+	sector := hue / 60
+	x := chroma * (1 - math.Abs(math.Mod(sector, 2)-1))
+
+	var r, g, b float64
+	switch int(sector) % 6 {
+	case 0:
+		r, g, b = chroma, x, 0
+	case 1:
+		r, g, b = x, chroma, 0
+	case 2:
+		r, g, b = 0, chroma, x
+	case 3:
+		r, g, b = 0, x, chroma
+	case 4:
+		r, g, b = x, 0, chroma
+	default:
+		r, g, b = chroma, 0, x
+	}
+
+	m := value - chroma
+	return RGBA{clampByte((r + m) * 255), clampByte((g + m) * 255), clampByte((b + m) * 255), 255}
+}
+
+// rgbToOKLab convert from sRGB, see https://bottosson.github.io/posts/oklab/
+func rgbToOKLab(color RGBA) (l float64, a float64, b float64) {
+	red, green, blue := srgbToLinear(color.R), srgbToLinear(color.G), srgbToLinear(color.B)
+
+	long := math.Cbrt(0.4122214708*red + 0.5363325363*green + 0.0514459929*blue)
+	medium := math.Cbrt(0.2119034982*red + 0.6806995451*green + 0.1073969566*blue)
+	short := math.Cbrt(0.0883024619*red + 0.2817188376*green + 0.6299787005*blue)
+
+	return 0.2104542553*long + 0.7936177850*medium - 0.0040720468*short,
+		1.9779984951*long - 2.4285922050*medium + 0.4505937099*short,
+		0.0259040371*long + 0.7827717662*medium - 0.8086757660*short
+}
+
+// okLabToRGB convert to an opaque sRGB color, clipping colors outside sRGB
+func okLabToRGB(l float64, a float64, b float64) RGBA {
+	long := l + 0.3963377774*a + 0.2158037573*b
+	medium := l - 0.1055613458*a - 0.0638541728*b
+	short := l - 0.0894841775*a - 1.2914855480*b
+	long, medium, short = long*long*long, medium*medium*medium, short*short*short
+
+	return RGBA{
+		linearToSrgb(4.0767416621*long - 3.3077115913*medium + 0.2309699292*short),
+		linearToSrgb(-1.2684380046*long + 2.6097574011*medium - 0.3413193965*short),
+		linearToSrgb(-0.0041960863*long - 0.7034186147*medium + 1.7076147010*short),
+		255,
+	}
+}
+
+// srgbToLinear undo the sRGB transfer curve
+func srgbToLinear(value uint8) float64 {
+	v := float64(value) / 255
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+// linearToSrgb apply the sRGB transfer curve
+func linearToSrgb(v float64) uint8 {
+	if v <= 0.0031308 {
+		return clampByte(v * 12.92 * 255)
+	}
+	return clampByte((1.055*math.Pow(v, 1/2.4) - 0.055) * 255)
+}
@@ -0,0 +1,118 @@
+package solar
+
+import (
+	"math"
+	"time"
+)
+
+// how long the alert plays when a countdown reaches zero
+const countdownAlertSeconds = 10.0
+
+// Countdown a timer counting down to zero, started, paused and reset from the api
+type Countdown struct {
+
+	// length of the countdown when reset
+	duration time.Duration
+
+	// time left while paused
+	remaining time.Duration
+
+	// when the countdown reaches zero while running
+	endsAt time.Time
+
+	running bool
+}
+
+// CountdownState json returned by /api/countdown, times are in seconds
+type CountdownState struct {
+	Duration  float64 `json:"duration"`
+	Remaining float64 `json:"remaining"`
+	Running   bool    `json:"running"`
+	Finished  bool    `json:"finished"`
+}
+
+// NewCountdown a paused five minute countdown
+func NewCountdown() *Countdown {
+	return &Countdown{duration: 5 * time.Minute, remaining: 5 * time.Minute}
+}
+
+// Start count down from where it was paused, or from the full duration once it has finished
+func (countdown *Countdown) Start(now time.Time) {
+	if countdown.running {
+		if !countdown.Finished(now) {
+			return
+		}
+		countdown.remaining = 0
+	}
+	if countdown.remaining <= 0 {
+		countdown.remaining = countdown.duration
+	}
+	countdown.endsAt = now.Add(countdown.remaining)
+	countdown.running = true
+}
+
+// Pause stop counting, keeping the time left
+func (countdown *Countdown) Pause(now time.Time) {
+	if !countdown.running {
+		return
+	}
+	countdown.remaining = countdown.Remaining(now)
+	countdown.running = false
+}
+
+// Reset stop and set the time left to duration, 0 keeps the current duration
+func (countdown *Countdown) Reset(duration time.Duration) {
+	if duration > 0 {
+		countdown.duration = duration
+	}
+	countdown.remaining = countdown.duration
+	countdown.running = false
+}
+
+// Remaining time left at now
+func (countdown *Countdown) Remaining(now time.Time) time.Duration {
+	if !countdown.running {
+		return countdown.remaining
+	}
+	if remaining := countdown.endsAt.Sub(now); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
+
+// Fraction of the countdown left at now, 1 when reset and 0 at the end
+func (countdown *Countdown) Fraction(now time.Time) float64 {
+	if countdown.duration <= 0 {
+		return 0
+	}
+	return math.Min(1, countdown.Remaining(now).Seconds()/countdown.duration.Seconds())
+}
+
+// Running if the countdown is counting down
+func (countdown *Countdown) Running() bool {
+	return countdown.running
+}
+
+// Finished if the countdown ran all the way to zero
+func (countdown *Countdown) Finished(now time.Time) bool {
+	return countdown.running && !now.Before(countdown.endsAt)
+}
+
+// alerting seconds since the countdown finished, false once the alert has played
+func (countdown *Countdown) alerting(now time.Time) (float64, bool) {
+	if !countdown.Finished(now) {
+		return 0, false
+	}
+	since := now.Sub(countdown.endsAt).Seconds()
+	return since, since < countdownAlertSeconds
+}
+
+// State the countdown at now, for the api
+func (countdown *Countdown) State(now time.Time) CountdownState {
+	return CountdownState{
+		Duration:  countdown.duration.Seconds(),
+		Remaining: countdown.Remaining(now).Seconds(),
+		Running:   countdown.running && !countdown.Finished(now),
+		Finished:  countdown.Finished(now),
+	}
+}
@@ -0,0 +1,77 @@
+package solar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DailyTime a time of day, either a clock time such as "20:30" or a sun event with an optional offset such as "sunset+30m"
+// Sun events are sunrise, sunset, dawn and dusk, where dawn and dusk are the start and end of civil twilight,
+// and nauticaldawn and nauticaldusk for the start and end of nautical twilight
+type DailyTime string
+
+// sun altitude and if it is the morning crossing, for each named sun event
+var sunEvents = map[string]struct {
+	altitude float64
+	morning  bool
+}{
+	"sunrise": {SunriseAltitude, true},
+	"sunset":  {SunriseAltitude, false},
+	"dawn":    {CivilTwilightAltitude, true},
+	"dusk":    {CivilTwilightAltitude, false},
+
+	"nauticaldawn": {NauticalTwilightAltitude, true},
+	"nauticaldusk": {NauticalTwilightAltitude, false},
+}
+
+// Validate returns an error if daily can not be parsed
+func (daily DailyTime) Validate() error {
+	_, _, err := daily.On(time.Now(), nil)
+	return err
+}
+
+// On returns the time of daily on the day of date at location
+// ok is false if the sun event does not happen on that day
+func (daily DailyTime) On(date time.Time, location *Location) (at time.Time, ok bool, err error) {
+	date = date.In(location.TimeLocation())
+	year, month, day := date.Date()
+
+	text := strings.ToLower(strings.TrimSpace(string(daily)))
+
+	if clock, err := time.Parse("15:04", text); err == nil {
+		return time.Date(year, month, day, clock.Hour(), clock.Minute(), 0, 0, date.Location()), true, nil
+	}
+
+	name, offsetText := text, ""
+	if split := strings.IndexAny(text, "+-"); split >= 0 {
+		name, offsetText = text[:split], text[split:]
+	}
+
+	event, found := sunEvents[name]
+	if !found {
+		return time.Time{}, false, fmt.Errorf("unknown time of day %q, expected hh:mm or a sun event like sunset+30m", string(daily))
+	}
+
+	var offset time.Duration
+	if offsetText != "" {
+		offset, err = time.ParseDuration(offsetText)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("time of day %q: %v", string(daily), err)
+		}
+	}
+
+	// sun events need a location, without one there is nothing to compute
+	if location == nil {
+		return time.Time{}, false, nil
+	}
+
+	rising, setting, ok := SunEvent(date, location, event.altitude)
+	if !ok {
+		return time.Time{}, false, nil
+	}
+	if event.morning {
+		return rising.Add(offset), true, nil
+	}
+	return setting.Add(offset), true, nil
+}
@@ -0,0 +1,124 @@
+package solar
+
+import (
+	"io"
+	"log"
+	"time"
+)
+
+// how long to wait between attempts to reopen a disconnected serial device
+const adalightReconnectInterval = 2 * time.Second
+
+// AdalightDisplay renders to an Arduino running the Adalight sketch over a serial port
+type AdalightDisplay struct {
+	// path to the tty device, such as /dev/ttyACM0
+	path string
+
+	// baud rate the sketch was compiled with
+	baud int
+
+	// open connection to the device, nil while disconnected
+	port io.WriteCloser
+
+	// when the device was last opened, used to throttle reconnect attempts
+	lastOpenAttempt time.Time
+
+	// where each led is wired, the arduino drives channel 0
+	stripMap *StripMap
+
+	// render color for each led
+	renderColor []RGBA
+
+	// color of each physical led on the strip
+	stripColor []RGBA
+
+	// header followed by the rgb data for each led, reused every frame
+	frame []byte
+}
+
+var _ Display = &AdalightDisplay{}
+
+// NewAdalightDisplay return new AdalightDisplay writing to the tty at path
+func NewAdalightDisplay(solarSystem *System, path string, baud int) *AdalightDisplay {
+	stripMap := solarSystem.StripMap()
+	if stripMap.ChannelCount() > 1 {
+		log.Print("Adalight only drives channel 0 of the ", stripMap.ChannelCount(), " channels in the strip")
+	}
+	ledCount := stripMap.ChannelLength(0)
+
+	display := &AdalightDisplay{
+		path:        path,
+		baud:        baud,
+		stripMap:    stripMap,
+		renderColor: make([]RGBA, solarSystem.LedCount()),
+		stripColor:  make([]RGBA, ledCount),
+		frame:       make([]byte, 6+3*ledCount),
+	}
+
+	// header is "Ada", led count - 1 as big endian 16 bit, then checksum of the count
+	countHigh := byte((ledCount - 1) >> 8)
+	countLow := byte((ledCount - 1) & 0xff)
+	copy(display.frame, []byte{'A', 'd', 'a', countHigh, countLow, countHigh ^ countLow ^ 0x55})
+
+	display.connect()
+	return display
+}
+
+// Dispose cleanup any resources
+func (display *AdalightDisplay) Dispose() {
+	if display.port == nil {
+		return
+	}
+
+	// leave the leds off when exiting
+	for i := 6; i < len(display.frame); i++ {
+		display.frame[i] = 0
+	}
+	display.port.Write(display.frame)
+
+	display.port.Close()
+	display.port = nil
+}
+
+// Render the field and send it to the device, reconnecting if it has gone away
+func (display *AdalightDisplay) Render(solarSystem *System) {
+	if display.port == nil && !display.connect() {
+		return
+	}
+
+	solarSystem.RenderColors(display.renderColor)
+	display.stripMap.ChannelColors(0, display.renderColor, display.stripColor)
+
+	for ledIndex, color := range display.stripColor {
+		red, green, blue := correctColor(color)
+
+		display.frame[6+3*ledIndex] = red
+		display.frame[6+3*ledIndex+1] = green
+		display.frame[6+3*ledIndex+2] = blue
+	}
+
+	_, err := display.port.Write(display.frame)
+	if err != nil {
+		log.Print("Lost adalight device ", display.path, ": ", err)
+		display.port.Close()
+		display.port = nil
+	}
+}
+
+// connect tries to open the serial device, returns if it is now connected
+func (display *AdalightDisplay) connect() bool {
+	if time.Since(display.lastOpenAttempt) < adalightReconnectInterval {
+		return false
+	}
+	display.lastOpenAttempt = time.Now()
+
+	port, err := openSerialPort(display.path, display.baud)
+	if err != nil {
+		log.Print("Unable to open adalight device ", display.path, ": ", err)
+		return false
+	}
+
+	log.Print("Connected to adalight device ", display.path, " at ", display.baud, " baud")
+	display.port = port
+	return true
+}
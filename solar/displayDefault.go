@@ -0,0 +1,16 @@
+// +build !windows
+
+package solar
+
+import (
+	"log"
+)
+
+// NewDisplay return new LedDisplay driving the strips of this machine
+func NewDisplay(solarSystem *System) *LedDisplay {
+	display, err := NewLedDisplay(solarSystem, newStripDriver())
+	if err != nil {
+		log.Fatal(err)
+	}
+	return display
+}
@@ -0,0 +1,121 @@
+package solar
+
+import (
+	"testing"
+)
+
+// renderFake render solarSystem once through a LedDisplay backed by a FakeDriver
+func renderFake(t *testing.T, solarSystem *System) *FakeDriver {
+	driver := &FakeDriver{}
+	display, err := NewLedDisplay(solarSystem, driver)
+	if err != nil {
+		t.Fatal(err)
+	}
+	display.Render(solarSystem)
+	return driver
+}
+
+// fillOverride every led of solarSystem set to color
+func fillOverride(solarSystem *System, color RGBA) []RGBA {
+	colors := make([]RGBA, solarSystem.LedCount())
+	for i := range colors {
+		colors[i] = color
+	}
+	solarSystem.SetLedOverride(colors)
+	return colors
+}
+
+func TestLedDisplayGamma(t *testing.T) {
+	tests := []struct {
+		color            RGBA
+		red, green, blue uint8
+	}{
+		{RGBA{R: 0, G: 0, B: 0, A: 255}, 0, 0, 0},
+		{RGBA{R: 255, G: 255, B: 255, A: 255}, 126, 126, 126},
+		{RGBA{R: 255, G: 128, B: 0, A: 255}, 126, 22, 0},
+		{RGBA{R: 200, G: 64, B: 255, A: 255}, 68, 4, 126},
+
+		// alpha scales the color before the gamma is applied
+		{RGBA{R: 255, G: 255, B: 0, A: 128}, 22, 22, 0},
+		{RGBA{R: 255, G: 255, B: 255, A: 0}, 0, 0, 0},
+	}
+
+	for _, test := range tests {
+		solarSystem := DefaultSystem()
+		fillOverride(solarSystem, test.color)
+
+		frame := renderFake(t, solarSystem).LastFrame()
+		want := uint32(test.green)<<16 | uint32(test.red)<<8 | uint32(test.blue)
+		for index, value := range frame[0] {
+			if value != want {
+				t.Fatalf("%v: led %d is %06x, want %06x", test.color, index, value, want)
+			}
+		}
+	}
+}
+
+func TestLedDisplayPacksGRB(t *testing.T) {
+	solarSystem := DefaultSystem()
+	fillOverride(solarSystem, RGBA{R: 255, G: 200, B: 64, A: 255})
+
+	value := renderFake(t, solarSystem).LastFrame()[0][0]
+	if red := uint8(value >> 8); red != 126 {
+		t.Errorf("red in the middle byte is %d, want 126", red)
+	}
+	if green := uint8(value >> 16); green != 68 {
+		t.Errorf("green in the high byte is %d, want 68", green)
+	}
+	if blue := uint8(value); blue != 4 {
+		t.Errorf("blue in the low byte is %d, want 4", blue)
+	}
+	if value>>24 != 0 {
+		t.Errorf("%08x uses more than 24 bits", value)
+	}
+}
+
+func TestLedDisplayPlanetOffsets(t *testing.T) {
+	solarSystem := DefaultSystem()
+
+	// first and last led of each planet in strip order
+	want := [PlanetCount][2]int{
+		Sun:     {0, 26},
+		Mercury: {27, 43},
+		Venus:   {44, 60},
+		Earth:   {61, 77},
+		Mars:    {78, 94},
+		Jupiter: {95, 121},
+		Saturn:  {122, 148},
+		Uranus:  {149, 175},
+		Neptune: {176, 202},
+	}
+
+	// light only the first and last led of each planet, with the planet in the green channel
+	colors := fillOverride(solarSystem, RGBA{A: 255})
+	offset := 0
+	for planetIndex := 0; planetIndex < PlanetCount; planetIndex++ {
+		ledCount := solarSystem.planets[planetIndex].ledCount
+		marker := RGBA{R: 255, G: uint8(40 + planetIndex*25), A: 255}
+		colors[offset] = marker
+		colors[offset+ledCount-1] = marker
+		offset += ledCount
+	}
+
+	frame := renderFake(t, solarSystem).LastFrame()
+	if len(frame[0]) != offset {
+		t.Fatalf("channel has %d leds, want %d", len(frame[0]), offset)
+	}
+
+	for planetIndex, ends := range want {
+		red, green, blue := correctColor(RGBA{R: 255, G: uint8(40 + planetIndex*25), A: 255})
+		marker := uint32(green)<<16 | uint32(red)<<8 | uint32(blue)
+
+		for _, index := range ends {
+			if frame[0][index] != marker {
+				t.Errorf("%v: led %d is %06x, want %06x", PlanetIndex(planetIndex), index, frame[0][index], marker)
+			}
+		}
+		if ends[1]+1 < len(frame[0]) && frame[0][ends[1]+1] == marker {
+			t.Errorf("%v: led %d after the last is lit", PlanetIndex(planetIndex), ends[1]+1)
+		}
+	}
+}
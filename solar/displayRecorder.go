@@ -0,0 +1,184 @@
+package solar
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"log"
+	"math"
+	"os"
+	"strings"
+)
+
+// RecorderDisplay renders each frame to an image and writes them out as an animated gif, apng or numbered pngs
+type RecorderDisplay struct {
+
+	// gif, apng or png
+	format string
+
+	// file written, for png this is the prefix of each numbered file
+	path string
+
+	// size of the output in pixels
+	width  int
+	height int
+
+	// pixels per unit of wall position
+	scale float64
+
+	// diameter of each led in pixels
+	dotSize float64
+
+	// frames per second of the recording
+	fps float64
+
+	// render color for each led
+	renderColor []RGBA
+
+	// number of frames rendered so far
+	frameCount int
+
+	// frames for a gif, converted to the palette as they are rendered
+	gifFrames []*image.Paletted
+
+	// compressed frames for an apng
+	apng *apngWriter
+
+	// first error hit while recording, reported once in Dispose
+	err error
+}
+
+var _ Display = &RecorderDisplay{}
+
+// NewRecorderDisplay create a RecorderDisplay that is width pixels across, recording at fps frames per second
+func NewRecorderDisplay(solarSystem *System, path string, format string, width int, dotSize float64, fps float64) (*RecorderDisplay, error) {
+	if format != "gif" && format != "apng" && format != "png" {
+		return nil, fmt.Errorf("unknown recording format %q", format)
+	}
+	if !(fps > 0 && fps <= 1000) {
+		return nil, fmt.Errorf("recording fps must be more than 0 and at most 1000")
+	}
+	if width <= 0 {
+		return nil, fmt.Errorf("recording width must be positive")
+	}
+
+	scale := float64(width) / WallWidth
+
+	display := &RecorderDisplay{
+		format:      format,
+		path:        path,
+		width:       width,
+		height:      int(math.Ceil(WallHeight * scale)),
+		scale:       scale,
+		dotSize:     dotSize,
+		fps:         fps,
+		renderColor: make([]RGBA, solarSystem.LedCount()),
+	}
+
+	if format == "apng" {
+		display.apng = &apngWriter{}
+		display.apng.delayNumerator, display.apng.delayDenominator = apngDelay(fps)
+	}
+
+	return display, nil
+}
+
+// apngDelay the frame delay for fps as a fraction of seconds, in hundredths when frames are slower than one a second
+func apngDelay(fps float64) (numerator uint16, denominator uint16) {
+	if fps >= 1 {
+		return 1, uint16(math.Round(fps))
+	}
+	return uint16(math.Min(65535, math.Round(100/fps))), 100
+}
+
+// Dispose write out the recording
+func (display *RecorderDisplay) Dispose() {
+	if display.err == nil {
+		switch display.format {
+		case "gif":
+			display.err = display.writeGif()
+		case "apng":
+			display.err = display.writeApng()
+		}
+	}
+
+	if display.err != nil {
+		log.Print("Recording to ", display.path, " failed: ", display.err)
+		return
+	}
+	log.Print("Recorded ", display.frameCount, " frames to ", display.path)
+}
+
+// Render the field to the next frame of the recording
+func (display *RecorderDisplay) Render(solarSystem *System) {
+	if display.err != nil {
+		return
+	}
+
+	frame := image.NewRGBA(image.Rect(0, 0, display.width, display.height))
+	draw.Draw(frame, frame.Bounds(), image.NewUniform(color.Black), image.Point{}, draw.Src)
+
+	solarSystem.RenderColors(display.renderColor)
+	drawFrame(solarSystem, display.renderColor, frame, display.scale, display.dotSize)
+
+	switch display.format {
+	case "gif":
+		paletted := image.NewPaletted(frame.Bounds(), palette.Plan9)
+		draw.Draw(paletted, frame.Bounds(), frame, image.Point{}, draw.Src)
+		display.gifFrames = append(display.gifFrames, paletted)
+	case "apng":
+		display.err = display.apng.addFrame(frame)
+	case "png":
+		display.err = display.writePng(frame)
+	}
+
+	display.frameCount++
+}
+
+// writeGif encode all of the frames as a looping gif
+func (display *RecorderDisplay) writeGif() error {
+	// gif delays are in hundredths of a second
+	delay := int(math.Round(100 / display.fps))
+
+	animation := &gif.GIF{}
+	for _, frame := range display.gifFrames {
+		animation.Image = append(animation.Image, frame)
+		animation.Delay = append(animation.Delay, delay)
+	}
+
+	file, err := os.Create(display.path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return gif.EncodeAll(file, animation)
+}
+
+// writeApng write out the frames collected by the apngWriter
+func (display *RecorderDisplay) writeApng() error {
+	file, err := os.Create(display.path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return display.apng.write(file)
+}
+
+// writePng write frame as the next numbered png, path frames.png becomes frames0000.png, frames0001.png, ...
+func (display *RecorderDisplay) writePng(frame *image.RGBA) error {
+	path := fmt.Sprintf("%s%04d.png", strings.TrimSuffix(display.path, ".png"), display.frameCount)
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return png.Encode(file, frame)
+}
@@ -0,0 +1,44 @@
+package solar
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNewRecorderDisplayValidates(t *testing.T) {
+	solarSystem := DefaultSystem()
+
+	for _, fps := range []float64{0, -1, 1001, 70000, math.NaN(), math.Inf(1)} {
+		if _, err := NewRecorderDisplay(solarSystem, "out.apng", "apng", 680, 4, fps); err == nil {
+			t.Errorf("fps %v accepted", fps)
+		}
+	}
+	for _, width := range []int{0, -10} {
+		if _, err := NewRecorderDisplay(solarSystem, "out.gif", "gif", width, 4, 30); err == nil {
+			t.Errorf("width %d accepted", width)
+		}
+	}
+	if _, err := NewRecorderDisplay(solarSystem, "out.gif", "gif", 680, 4, 1000); err != nil {
+		t.Errorf("fps 1000: %v", err)
+	}
+}
+
+func TestApngDelay(t *testing.T) {
+	tests := []struct {
+		fps                    float64
+		numerator, denominator uint16
+	}{
+		{30, 1, 30},
+		{1000, 1, 1000},
+		{1, 1, 1},
+		{0.5, 200, 100},
+		{0.3, 333, 100},
+		{0.001, 65535, 100},
+	}
+	for _, test := range tests {
+		numerator, denominator := apngDelay(test.fps)
+		if numerator != test.numerator || denominator != test.denominator {
+			t.Errorf("fps %v delay %d/%d, want %d/%d", test.fps, numerator, denominator, test.numerator, test.denominator)
+		}
+	}
+}
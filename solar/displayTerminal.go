@@ -0,0 +1,128 @@
+package solar
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+
+	"github.com/golang/geo/r2"
+)
+
+// TerminalDisplay draws the wall in a truecolor terminal, each character cell is two pixels using the upper half block
+type TerminalDisplay struct {
+	out *bufio.Writer
+
+	// size of the character grid
+	columns int
+	rows    int
+
+	// scale from wall position to pixel
+	scale r2.Point
+
+	// render color for each led
+	renderColor []RGBA
+
+	// pixel grid, two pixels tall per row of characters, nil where there is no led
+	pixels []*RGBA
+}
+
+var _ Display = &TerminalDisplay{}
+
+// NewTerminalDisplay create a new TerminalDisplay that is columns characters wide
+func NewTerminalDisplay(solarSystem *System, columns int) *TerminalDisplay {
+	scale := float64(columns) / WallWidth
+	rows := int(WallHeight*scale+1) / 2
+
+	display := &TerminalDisplay{
+		out:         bufio.NewWriterSize(os.Stdout, 64*1024),
+		columns:     columns,
+		rows:        rows,
+		scale:       r2.Point{X: scale, Y: scale},
+		renderColor: make([]RGBA, solarSystem.LedCount()),
+		pixels:      make([]*RGBA, columns*rows*2),
+	}
+
+	// clear the screen and hide the cursor while drawing
+	display.out.WriteString("\x1b[2J\x1b[?25l")
+	display.out.Flush()
+
+	return display
+}
+
+// Dispose restore the terminal
+func (display *TerminalDisplay) Dispose() {
+	display.out.WriteString("\x1b[0m\x1b[?25h\n")
+	display.out.Flush()
+}
+
+// Render the field to the terminal, overwriting the previous frame
+func (display *TerminalDisplay) Render(solarSystem *System) {
+	solarSystem.RenderColors(display.renderColor)
+
+	for i := range display.pixels {
+		display.pixels[i] = nil
+	}
+
+	// place each led at its position on the wall
+	ledIndex := 0
+	for planetIndex := 0; planetIndex < PlanetCount; planetIndex++ {
+		for led := 0; led < solarSystem.planets[planetIndex].ledCount; led++ {
+			ledPosition := solarSystem.LedPosition(PlanetIndex(planetIndex), led)
+
+			x := int(ledPosition.X * display.scale.X)
+			y := int(ledPosition.Y * display.scale.Y)
+			if x >= 0 && x < display.columns && y >= 0 && y < display.rows*2 {
+				display.pixels[y*display.columns+x] = &display.renderColor[ledIndex]
+			}
+
+			ledIndex++
+		}
+	}
+
+	display.out.WriteString("\x1b[H")
+	for row := 0; row < display.rows; row++ {
+		// only reset attributes when leaving a run of colored cells
+		colored := false
+		for column := 0; column < display.columns; column++ {
+			top := display.pixels[(row*2)*display.columns+column]
+			bottom := display.pixels[(row*2+1)*display.columns+column]
+
+			if top == nil && bottom == nil {
+				if colored {
+					display.out.WriteString("\x1b[0m")
+					colored = false
+				}
+				display.out.WriteString(" ")
+				continue
+			}
+
+			display.writeColor(38, top)
+			display.writeColor(48, bottom)
+			display.out.WriteString("▀")
+			colored = true
+		}
+		display.out.WriteString("\x1b[0m\n")
+	}
+	display.out.Flush()
+}
+
+// writeColor write the escape code setting the foreground (38) or background (48) to color, black if there is no led
+func (display *TerminalDisplay) writeColor(code int, color *RGBA) {
+	var red, green, blue uint8
+	if color != nil {
+		red, green, blue = color.R, color.G, color.B
+	}
+
+	buffer := make([]byte, 0, 20)
+	buffer = append(buffer, "\x1b["...)
+	buffer = strconv.AppendInt(buffer, int64(code), 10)
+	buffer = append(buffer, ";2;"...)
+	buffer = strconv.AppendUint(buffer, uint64(red), 10)
+	buffer = append(buffer, ';')
+	buffer = strconv.AppendUint(buffer, uint64(green), 10)
+	buffer = append(buffer, ';')
+	buffer = strconv.AppendUint(buffer, uint64(blue), 10)
+	buffer = append(buffer, 'm')
+
+	display.out.Write(buffer)
+}
@@ -5,7 +5,6 @@ package solar
 import (
 	"fmt"
 	"image"
-	"image/color"
 	"image/png"
 	"log"
 	"net/http"
@@ -17,6 +16,7 @@ import (
 // WebDisplay info needed to render to an image
 type WebDisplay struct {
 	colorSamples []r2.Point
+	renderColor  []RGBA
 	width        int
 	height       int
 
@@ -37,11 +37,12 @@ var testWebDisplay Display = &WebDisplay{}
 // NewDisplay create a new WebDisplay
 func NewDisplay(solarSystem *System) *WebDisplay {
 
-	width := 136
-	height := 91
+	width := WallWidth
+	height := WallHeight
 
 	display := &WebDisplay{
 		colorSamples: make([]r2.Point, solarSystem.LedCount()),
+		renderColor:  make([]RGBA, solarSystem.LedCount()),
 		width:        width * 5,
 		height:       height * 5,
 		scale:        r2.Point{X: 5, Y: 5},
@@ -50,7 +51,7 @@ func NewDisplay(solarSystem *System) *WebDisplay {
 		image:        image.NewRGBA(image.Rect(0, 0, width*5, height*5)),
 	}
 
-	go display.LaunchWebServer()
+	display.registerHandlers()
 	return display
 }
 
@@ -64,58 +65,18 @@ func (display *WebDisplay) Render(solarSystem *System) {
 
 	image := image.NewRGBA(image.Rect(0, 0, display.width, display.height))
 
-	// loop through every planet
-	for planetIndex := 0; planetIndex < PlanetCount; planetIndex++ {
-		planet := display.solarSystem.planets[planetIndex]
-		//pos := planet.position
+	solarSystem.RenderColors(display.renderColor)
 
-		// initialize default color for each led position
-		for led := 0; led < planet.ledCount; led++ {
-			ledPosition := solarSystem.LedPosition(PlanetIndex(planetIndex), led)
-
-			imageX := int(ledPosition.X * display.scale.X)
-			imageY := int(ledPosition.Y * display.scale.Y)
-
-			image.SetRGBA(imageX, imageY, color.RGBA{R: 0, G: 0, B: 0, A: 255})
-		}
-
-		// loop through every drawable object
-		for curElement := solarSystem.drawables.Front(); curElement != nil; curElement = curElement.Next() {
-			drawable := curElement.Value.(Drawable)
-
-			// for now want default color to be set for all leds
-			// bounding circle check to see if this should affect this planet
-			//if !drawable.Affects(pos, planet.radius) {
-			//	continue
-			//}
-
-			// loop through every led on this planet
-			for led := 0; led < planet.ledCount; led++ {
-				ledPosition := solarSystem.LedPosition(PlanetIndex(planetIndex), led)
-
-				imageX := int(ledPosition.X * display.scale.X)
-				imageY := int(ledPosition.Y * display.scale.Y)
-
-				curColor := RGBA(image.RGBAAt(imageX, imageY))
-				curColor = drawable.ColorAt(ledPosition, RGBA(curColor))
-
-				curColor.A = 255 // for rendering to image dont want to blend to nothing
-				image.SetRGBA(imageX, imageY, color.RGBA(curColor))
-			}
-		}
-	}
+	drawFrame(solarSystem, display.renderColor, image, display.scale.X, 1)
 
 	display.image = image
 }
 
-// LaunchWebServer Launches the webserver
-func (display *WebDisplay) LaunchWebServer() {
+// registerHandlers adds the preview page to the webserver started by LaunchWebServer
+func (display *WebDisplay) registerHandlers() {
 
 	http.HandleFunc("/", htmlPageHandler)
 	http.HandleFunc("/image/", func(w http.ResponseWriter, r *http.Request) { display.imageHandler(w, r) })
-
-	log.Print("Server listening on 8080")
-	log.Fatal(http.ListenAndServe(":8080", nil))
 }
 
 // Serve static html page
@@ -129,8 +90,17 @@ func htmlPageHandler(w http.ResponseWriter, r *http.Request) {
 			setTimeout(reloadpic, 400);
         }
         setTimeout(reloadpic, 400)
+
+		function reloadmoon()
+		{
+			fetch("api/moon").then(function(response) { return response.json(); }).then(function(moon) {
+				document.getElementById("moon").textContent = moon.phase + ", " + Math.round(moon.illuminated * 100) + "%% lit, " + new Date(moon.time).toLocaleString();
+			});
+			setTimeout(reloadmoon, 1000);
+		}
+		setTimeout(reloadmoon, 0)
 	--></script></head>
-	<body bgcolor="#888888"><img id="gameBoard" src="image/test.png" height="910" width="1360"/></body>
+	<body bgcolor="#888888"><img id="gameBoard" src="image/test.png" height="910" width="1360"/><p id="moon"></p></body>
 </html>`)
 
 }
@@ -0,0 +1,70 @@
+package solar
+
+// DrawAnimated wraps a Drawable, driving its properties from timelines before it animates itself
+type DrawAnimated struct {
+	Drawable
+
+	// seconds since the timelines started
+	elapsed float64
+
+	// each property being driven
+	tracks []animatedTrack
+
+	// remove the drawable once every timeline has finished
+	removeWhenDone bool
+}
+
+// animatedTrack one property driven by a timeline, only one of the setters is used
+type animatedTrack struct {
+	timeline *Timeline
+	setValue func(float64)
+	setColor func(RGBA)
+}
+
+var _ Drawable = &DrawAnimated{}
+
+// Animated wrap drawable so its properties can be driven by timelines
+func Animated(drawable Drawable) *DrawAnimated {
+	return &DrawAnimated{Drawable: drawable}
+}
+
+// Value drive a number property, set is called every frame with the timeline's value
+func (animated *DrawAnimated) Value(timeline *Timeline, set func(float64)) *DrawAnimated {
+	animated.tracks = append(animated.tracks, animatedTrack{timeline: timeline, setValue: set})
+	set(timeline.Value(animated.elapsed))
+	return animated
+}
+
+// Color drive a color property, set is called every frame with the timeline's color
+func (animated *DrawAnimated) Color(timeline *Timeline, set func(RGBA)) *DrawAnimated {
+	animated.tracks = append(animated.tracks, animatedTrack{timeline: timeline, setColor: set})
+	set(timeline.Color(animated.elapsed))
+	return animated
+}
+
+// RemoveWhenDone remove the drawable once every timeline has finished
+func (animated *DrawAnimated) RemoveWhenDone() *DrawAnimated {
+	animated.removeWhenDone = true
+	return animated
+}
+
+// Animate move the timelines forward then the drawable itself
+func (animated *DrawAnimated) Animate(dt float64) bool {
+	animated.elapsed += dt
+
+	finished := true
+	for _, track := range animated.tracks {
+		if track.setValue != nil {
+			track.setValue(track.timeline.Value(animated.elapsed))
+		} else {
+			track.setColor(track.timeline.Color(animated.elapsed))
+		}
+		finished = finished && track.timeline.Finished(animated.elapsed)
+	}
+
+	keepAlive := animated.Drawable.Animate(dt)
+	if animated.removeWhenDone && finished {
+		return false
+	}
+	return keepAlive
+}
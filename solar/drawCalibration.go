@@ -0,0 +1,80 @@
+package solar
+
+import (
+	"math"
+
+	"github.com/golang/geo/r2"
+)
+
+// DrawCalibration lights up the leds of one planet needed to find its angleOffset and angleDirection
+// green is led 0, red is the led the layout places at the top of the planet and blue is the led it places on the right
+type DrawCalibration struct {
+	solarSystem *System
+
+	// planet being calibrated
+	planet PlanetIndex
+
+	// z position of the markers
+	zindex ZIndex
+}
+
+var _ Drawable = &DrawCalibration{}
+
+// NewCalibrationMarkers Construct markers on planet
+func NewCalibrationMarkers(planet PlanetIndex, solarSystem *System) *DrawCalibration {
+	return &DrawCalibration{
+		solarSystem: solarSystem,
+		planet:      planet,
+		zindex:      1000,
+	}
+}
+
+// Affects only the planet being calibrated
+func (calibration *DrawCalibration) Affects(position r2.Point, radius float64) bool {
+	return position == calibration.solarSystem.planets[calibration.planet].position
+}
+
+// ColorAt Returns the color at position blended on top of baseColor
+func (calibration *DrawCalibration) ColorAt(position r2.Point, baseColor RGBA) (color RGBA) {
+	planet := calibration.solarSystem.planets[calibration.planet]
+
+	if position.Sub(calibration.solarSystem.LedPosition(calibration.planet, 0)).Norm() < 1e-6 {
+		return RGBA{R: 0, G: 255, B: 0, A: 255}
+	}
+
+	// half the angle between leds, so exactly one led is closest to each marker
+	halfLedAngle := math.Pi / float64(planet.ledCount)
+	angle := math.Atan2(position.Y-planet.position.Y, position.X-planet.position.X)
+
+	// y increases down the wall, so the top is at -pi/2
+	if math.Abs(angleDifference(angle, -math.Pi/2)) < halfLedAngle {
+		return RGBA{R: 255, G: 0, B: 0, A: 255}
+	}
+	if math.Abs(angleDifference(angle, 0)) < halfLedAngle {
+		return RGBA{R: 0, G: 0, B: 255, A: 255}
+	}
+
+	// dimly light the rest of the ring so it is clear which planet is selected
+	return RGBA{R: 40, G: 40, B: 40, A: 255}
+}
+
+// ZIndex of the markers
+func (calibration *DrawCalibration) ZIndex() ZIndex {
+	return calibration.zindex
+}
+
+// Animate markers, they stay until calibration is done
+func (calibration *DrawCalibration) Animate(dt float64) bool {
+	return true
+}
+
+// angleDifference returns a - b wrapped into -pi to pi
+func angleDifference(a, b float64) float64 {
+	difference := math.Mod(a-b, 2*math.Pi)
+	if difference > math.Pi {
+		difference -= 2 * math.Pi
+	} else if difference < -math.Pi {
+		difference += 2 * math.Pi
+	}
+	return difference
+}
@@ -2,6 +2,7 @@ package solar
 
 import (
 	"image/color"
+	"math"
 
 	"github.com/golang/geo/r2"
 )
@@ -24,7 +25,7 @@ type DrawCircle struct {
 	// if the ball should be hidden this frame or not
 	hideBall bool
 
-	// rate that influence falls off
+	// width of the ring, influence falls off to nothing this far from the radius
 	falloff float64
 
 	//
@@ -45,6 +46,18 @@ func NewCircle(solarSystem *System) *DrawCircle {
 	}
 }
 
+// NewRipple Construct a ring that expands from position at velocity, fading out as it reaches maxRadius
+func NewRipple(position r2.Point, velocity float64, maxRadius float64, width float64, color color.RGBA) *DrawCircle {
+	return &DrawCircle{
+		position:  position,
+		velocity:  velocity,
+		maxRadius: maxRadius,
+		falloff:   width,
+		color:     color,
+		zindex:    3,
+	}
+}
+
 // Affects returns bounding circle check
 func (circle *DrawCircle) Affects(position r2.Point, radius float64) bool {
 	distance := circle.position.Sub(position)
@@ -53,8 +66,21 @@ func (circle *DrawCircle) Affects(position r2.Point, radius float64) bool {
 
 // ColorAt Returns the color at position blended on top of baseColor
 func (circle *DrawCircle) ColorAt(position r2.Point, baseColor RGBA) (color RGBA) {
+	if circle.hideBall || circle.falloff <= 0 {
+		return baseColor
+	}
+
+	fromRing := math.Abs(position.Sub(circle.position).Norm() - circle.radius)
+	if fromRing >= circle.falloff {
+		return baseColor
+	}
 
-	return baseColor
+	fade := 1.0
+	if circle.maxRadius > 0 {
+		fade = math.Max(0, 1-circle.radius/circle.maxRadius)
+	}
+	color = RGBA{circle.color.R, circle.color.G, circle.color.B, uint8((1 - fromRing/circle.falloff) * fade * float64(circle.color.A))}
+	return color.BlendWith(baseColor)
 }
 
 // ZIndex of the circle
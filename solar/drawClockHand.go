@@ -0,0 +1,83 @@
+package solar
+
+import (
+	"math"
+	"time"
+)
+
+// ClockUnit what a clock hand shows
+type ClockUnit int
+
+// Clock hands
+const (
+	ClockHours ClockUnit = iota
+	ClockMinutes
+	ClockSeconds
+)
+
+// ClockLayout how the clock scene tells time
+type ClockLayout struct {
+
+	// 12 for the hour hand to turn twice a day, 24 to turn once with midnight at the top
+	Hours int `json:"hours"`
+}
+
+// DefaultClockLayout a 12 hour clock
+var DefaultClockLayout = ClockLayout{Hours: 12}
+
+// DrawClockHand a rotating line on a planet that points at the current hour, minute or second in the wall's time zone
+// 12 o'clock is straight up the wall, LedPosition maps that through the angleOffset and angleDirection of each planet
+type DrawClockHand struct {
+	*DrawRotatingLine
+
+	// system the time zone and clock face are read from
+	solarSystem *System
+
+	// what the hand shows
+	unit ClockUnit
+}
+
+var _ Drawable = &DrawClockHand{}
+
+// NewClockHand Construct a hand on planet showing unit
+func NewClockHand(planet PlanetIndex, unit ClockUnit, color RGBA, solarSystem *System) *DrawClockHand {
+	line := NewRotatingLine(planet, solarSystem)
+	line.traverseTime = 0
+	line.length = solarSystem.planets[planet].radius
+	line.lineWidth = 1.2
+	line.SetColor(color)
+
+	hand := &DrawClockHand{DrawRotatingLine: line, solarSystem: solarSystem, unit: unit}
+	hand.point(time.Now())
+	return hand
+}
+
+// Animate point the hand at the current time
+func (hand *DrawClockHand) Animate(dt float64) bool {
+	hand.point(time.Now())
+	return hand.DrawRotatingLine.Animate(dt)
+}
+
+// point the hand at now in the wall's time zone
+func (hand *DrawClockHand) point(now time.Time) {
+	hand.SetAngle(clockAngle(now.In(hand.solarSystem.location.TimeLocation()), hand.unit, hand.solarSystem.clockFace.Hours) - math.Pi/2)
+}
+
+// clockAngle radians clockwise from 12 o'clock of a hand showing unit at local time t, moving smoothly between marks
+func clockAngle(t time.Time, unit ClockUnit, hours int) float64 {
+	seconds := float64(t.Second()) + float64(t.Nanosecond())/1e9
+	minutes := float64(t.Minute()) + seconds/60
+
+	turn := 0.0
+	switch unit {
+	case ClockHours:
+		turn = math.Mod(float64(t.Hour())+minutes/60, float64(hours)) / float64(hours)
+	case ClockMinutes:
+		turn = minutes / 60
+	case ClockSeconds:
+		turn = seconds / 60
+	}
+
+	// y grows down the wall, so increasing angles already turn clockwise
+	return turn * 2 * math.Pi
+}
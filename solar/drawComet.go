@@ -0,0 +1,272 @@
+package solar
+
+import (
+	"image/color"
+	"math"
+	"math/rand"
+
+	"github.com/golang/geo/r2"
+)
+
+// distance from the Sun beyond which a comet can never be on the wall, the wall diagonal
+var cometExitDistance = math.Hypot(WallWidth, WallHeight)
+
+// DrawComet renders a comet swinging past the Sun on an elliptical or parabolic orbit
+// The bright head trails a fading tail that always points away from the Sun, and lights any planet it passes over
+type DrawComet struct {
+
+	// system sparks are added to when the comet strikes Jupiter
+	solarSystem *System
+
+	// if the comet has already struck Jupiter
+	struck bool
+
+	// focus of the orbit
+	sun r2.Point
+
+	// closest distance to the sun
+	perihelion float64
+
+	// shape of the orbit, below 1 is an ellipse and 1 a parabola
+	eccentricity float64
+
+	// radians direction from the sun to the perihelion
+	axisAngle float64
+
+	// 1 to travel counter clockwise around the sun, -1 for clockwise
+	direction float64
+
+	// true anomaly of the comet, radians from perihelion along the orbit
+	anomaly float64
+
+	// anomaly where the orbit leaves the wall, the comet travels from -maxAnomaly to maxAnomaly
+	maxAnomaly float64
+
+	// speed at perihelion in distance per second, slower further out
+	speed float64
+
+	// position of the head
+	head r2.Point
+
+	// radius of the bright head
+	headRadius float64
+
+	// radius around the head that softly lights planets
+	glowRadius float64
+
+	// length of the tail when closest to the sun
+	tailLength float64
+
+	// color of the head
+	headColor color.RGBA
+
+	// color of the tail
+	tailColor color.RGBA
+
+	// z position of comet
+	zindex ZIndex
+}
+
+var _ Drawable = &DrawComet{}
+
+// NewComet Construct a comet on a random orbit that crosses the wall
+func NewComet(solarSystem *System) *DrawComet {
+	perihelion := 6 + rand.Float64()*60
+
+	// the orbit has to reach past the wall, or the comet would appear out of nowhere at aphelion
+	minEccentricity := math.Max(0.8, (cometExitDistance-perihelion)/(cometExitDistance+perihelion)+0.01)
+
+	direction := 1.0
+	if rand.Intn(2) == 0 {
+		direction = -1.0
+	}
+
+	comet := &DrawComet{
+		solarSystem:  solarSystem,
+		sun:          solarSystem.planets[Sun].position,
+		perihelion:   perihelion,
+		eccentricity: minEccentricity + rand.Float64()*(1-minEccentricity),
+		axisAngle:    math.Atan2(WallHeight, WallWidth) + (rand.Float64()-0.5)*math.Pi/2, // through the wall, away from the sun in the corner
+		direction:    direction,
+		speed:        30 + rand.Float64()*30,
+		headRadius:   4.0,
+		glowRadius:   12.0,
+		tailLength:   40.0,
+		headColor:    color.RGBA{R: 220, G: 240, B: 255, A: 255},
+		tailColor:    color.RGBA{R: 100, G: 170, B: 255, A: 200},
+		zindex:       5,
+	}
+	comet.SetOrbit(comet.perihelion, comet.eccentricity, comet.axisAngle)
+	return comet
+}
+
+// SetOrbit change the orbit and start the comet again from where it enters the wall
+func (comet *DrawComet) SetOrbit(perihelion float64, eccentricity float64, axisAngle float64) {
+	comet.perihelion = perihelion
+	comet.eccentricity = math.Max(0, math.Min(1, eccentricity))
+	comet.axisAngle = axisAngle
+
+	// solve r(anomaly) = exit distance, an ellipse that never gets that far is shown in full
+	cosine := (comet.perihelion*(1+comet.eccentricity)/cometExitDistance - 1) / math.Max(comet.eccentricity, 0.0001)
+	comet.maxAnomaly = math.Acos(math.Max(-1, math.Min(1, cosine)))
+
+	// skip the part of the orbit before it comes near the wall
+	comet.anomaly = -comet.maxAnomaly
+	for comet.anomaly < 0 && !comet.nearWall(comet.positionAt(comet.anomaly)) {
+		comet.anomaly += 0.01
+	}
+	comet.head = comet.positionAt(comet.anomaly)
+}
+
+// nearWall if a head at position could light any of the wall
+func (comet *DrawComet) nearWall(position r2.Point) bool {
+	margin := comet.tailLength + comet.glowRadius
+	return position.X > -margin && position.X < WallWidth+margin && position.Y > -margin && position.Y < WallHeight+margin
+}
+
+// positionAt point on the orbit at anomaly radians from perihelion
+func (comet *DrawComet) positionAt(anomaly float64) r2.Point {
+	distance := comet.perihelion * (1 + comet.eccentricity) / (1 + comet.eccentricity*math.Cos(anomaly))
+	angle := comet.axisAngle + anomaly*comet.direction
+	return r2.Point{X: math.Cos(angle) * distance, Y: math.Sin(angle) * distance}.Add(comet.sun)
+}
+
+// tail unit direction away from the sun and the current length, longest near the sun
+func (comet *DrawComet) tail() (direction r2.Point, length float64) {
+	fromSun := comet.head.Sub(comet.sun)
+	distance := fromSun.Norm()
+	if distance == 0 {
+		return r2.Point{X: 1, Y: 0}, comet.tailLength
+	}
+	return fromSun.Mul(1 / distance), comet.tailLength * math.Max(0.3, math.Min(1, 2*comet.perihelion/distance))
+}
+
+// Affects returns bounding check against the head glow and the tail
+func (comet *DrawComet) Affects(position r2.Point, radius float64) bool {
+	direction, length := comet.tail()
+	along := math.Max(0, math.Min(length, position.Sub(comet.head).Dot(direction)))
+	closest := comet.head.Add(direction.Mul(along))
+	return closest.Sub(position).Norm() < radius+comet.glowRadius+comet.headRadius*2.5
+}
+
+// ColorAt Returns the color at position blended on top of baseColor
+func (comet *DrawComet) ColorAt(position r2.Point, baseColor RGBA) (color RGBA) {
+	offset := position.Sub(comet.head)
+	headDistance := offset.Norm()
+
+	// the head, with a soft glow around it so planets brighten as it nears
+	if headDistance < comet.headRadius {
+		alpha := 1 - headDistance/comet.headRadius*0.5
+		color = RGBA{comet.headColor.R, comet.headColor.G, comet.headColor.B, uint8(alpha * float64(comet.headColor.A))}
+		return color.BlendWith(baseColor)
+	}
+	if headDistance < comet.glowRadius {
+		baseColor = RGBA{comet.headColor.R, comet.headColor.G, comet.headColor.B, uint8((1 - headDistance/comet.glowRadius) * 80)}.BlendWith(baseColor)
+	}
+
+	// the tail widens and fades further from the head
+	direction, length := comet.tail()
+	along := offset.Dot(direction)
+	if along <= 0 || along >= length {
+		return baseColor
+	}
+	fraction := along / length
+	halfWidth := comet.headRadius * (1 + 1.5*fraction)
+	across := math.Abs(offset.Cross(direction))
+	if across >= halfWidth {
+		return baseColor
+	}
+
+	alpha := (1 - fraction) * (1 - fraction) * (1 - across/halfWidth)
+	color = RGBA{comet.tailColor.R, comet.tailColor.G, comet.tailColor.B, uint8(alpha * float64(comet.tailColor.A))}
+	return color.BlendWith(baseColor)
+}
+
+// ZIndex of the comet
+func (comet *DrawComet) ZIndex() ZIndex {
+	return comet.zindex
+}
+
+// Animate comet along its orbit, removing it once it has left the wall
+func (comet *DrawComet) Animate(dt float64) bool {
+	// faster near the sun like a real orbit, speed falls off with the square root of distance
+	distance := comet.head.Sub(comet.sun).Norm()
+	travel := comet.speed * math.Sqrt(comet.perihelion/math.Max(distance, comet.perihelion)) * dt
+
+	// convert the distance to travel into a change of anomaly using the local length of the orbit
+	const step = 0.001
+	stepLength := comet.positionAt(comet.anomaly + step).Sub(comet.head).Norm()
+	if stepLength > 0 {
+		comet.anomaly += travel / stepLength * step
+	}
+	comet.head = comet.positionAt(comet.anomaly)
+
+	jupiter := comet.solarSystem.planets[Jupiter]
+	if !comet.struck && comet.head.Sub(jupiter.position).Norm() < jupiter.radius {
+		comet.struck = true
+		comet.solarSystem.AddDrawable(NewParticles(comet.head).
+			For(0).
+			Life(1.2, 0.4).
+			Velocity(0, math.Pi, 20, 10).
+			Gravity(jupiter.position, 15).
+			ColorOverLife(NewTimeline(
+				Keyframe{Time: 0, Color: RGBA{R: 255, G: 255, B: 200, A: 255}},
+				Keyframe{Time: 1, Color: RGBA{R: 255, G: 80, B: 0, A: 0}},
+			)).
+			Burst(40))
+	}
+
+	return comet.anomaly < comet.maxAnomaly && (comet.anomaly < 0 || comet.nearWall(comet.head))
+}
+
+// DrawCometSpawner adds a new comet to the system every so often, it draws nothing itself
+type DrawCometSpawner struct {
+
+	// system comets are added to
+	solarSystem *System
+
+	// average seconds between comets
+	interval float64
+
+	// seconds until the next comet
+	untilNext float64
+}
+
+var _ Drawable = &DrawCometSpawner{}
+
+// NewCometSpawner Construct a spawner adding a comet about every interval seconds
+func NewCometSpawner(solarSystem *System, interval float64) *DrawCometSpawner {
+	spawner := &DrawCometSpawner{solarSystem: solarSystem, interval: interval}
+	spawner.untilNext = spawner.nextDelay()
+	return spawner
+}
+
+// nextDelay random time until the next comet, so they arrive unpredictably
+func (spawner *DrawCometSpawner) nextDelay() float64 {
+	return spawner.interval * (0.5 + rand.ExpFloat64()*0.5)
+}
+
+// Affects nothing
+func (spawner *DrawCometSpawner) Affects(position r2.Point, radius float64) bool {
+	return false
+}
+
+// ColorAt Returns baseColor
+func (spawner *DrawCometSpawner) ColorAt(position r2.Point, baseColor RGBA) (color RGBA) {
+	return baseColor
+}
+
+// ZIndex of the spawner
+func (spawner *DrawCometSpawner) ZIndex() ZIndex {
+	return 0
+}
+
+// Animate spawner, adding a comet when it is due
+func (spawner *DrawCometSpawner) Animate(dt float64) bool {
+	spawner.untilNext -= dt
+	if spawner.untilNext <= 0 {
+		spawner.solarSystem.AddDrawable(NewComet(spawner.solarSystem))
+		spawner.untilNext = spawner.nextDelay()
+	}
+	return true
+}
@@ -0,0 +1,126 @@
+package solar
+
+import (
+	"image/color"
+	"math"
+	"time"
+
+	"github.com/golang/geo/r2"
+)
+
+// DrawCountdown renders the time left on the system countdown, either as planets going dark one by one from Neptune
+// or as a ring draining around the Sun, then flashes every planet when it reaches zero
+type DrawCountdown struct {
+
+	// system the countdown is read from and the alert ripple is added to
+	solarSystem *System
+
+	// drain a ring around the Sun instead of turning planets off
+	ring bool
+
+	// seconds since the countdown started drawing, for breathing while paused
+	time float64
+
+	// fraction of the countdown left and seconds into the alert, updated by Animate
+	fraction float64
+	alert    float64
+	alerting bool
+
+	// if the ripple of the current alert has been added
+	rippled bool
+
+	// z position of countdown
+	zindex ZIndex
+}
+
+var _ Drawable = &DrawCountdown{}
+
+// NewCountdownDisplay Construct a countdown shown on the planets, or on a ring around the Sun
+func NewCountdownDisplay(solarSystem *System, ring bool) *DrawCountdown {
+	countdown := &DrawCountdown{solarSystem: solarSystem, ring: ring, zindex: 5}
+	countdown.Animate(0)
+	return countdown
+}
+
+// Affects every planet
+func (countdown *DrawCountdown) Affects(position r2.Point, radius float64) bool {
+	return true
+}
+
+// ColorAt Returns the color at position blended on top of baseColor
+func (countdown *DrawCountdown) ColorAt(position r2.Point, baseColor RGBA) (color RGBA) {
+	if countdown.alerting {
+		// alternate red and white twice a second
+		color = RGBA{R: 255, G: 0, B: 0, A: 255}
+		if math.Mod(countdown.alert, 0.5) < 0.25 {
+			color = RGBA{R: 255, G: 255, B: 255, A: 255}
+		}
+		return color.BlendWith(baseColor)
+	}
+
+	// green when there is plenty of time, through yellow to red near the end
+	color = HSV(120*countdown.fraction, 1, 1)
+	if !countdown.solarSystem.Countdown().Running() {
+		color.A = uint8(120 + 100*math.Sin(countdown.time*2)) // breathe while paused
+	}
+
+	planet, ok := countdown.planetAt(position)
+	if !ok {
+		return baseColor
+	}
+
+	if countdown.ring {
+		if planet != Sun {
+			return baseColor
+		}
+
+		// lit clockwise from 12 o'clock up to the time left
+		offset := position.Sub(countdown.solarSystem.planets[Sun].position)
+		turn := math.Mod(math.Atan2(offset.Y, offset.X)+math.Pi/2+2*math.Pi, 2*math.Pi) / (2 * math.Pi)
+		if turn > countdown.fraction {
+			return baseColor
+		}
+		return color.BlendWith(baseColor)
+	}
+
+	// each planet is a ninth of the countdown, the one being used up fades out
+	planetFraction := countdown.fraction*float64(PlanetCount) - float64(planet)
+	if planetFraction <= 0 {
+		return baseColor
+	}
+	color.A = uint8(float64(color.A) * math.Min(1, planetFraction))
+	return color.BlendWith(baseColor)
+}
+
+// planetAt the planet whose leds are at position
+func (countdown *DrawCountdown) planetAt(position r2.Point) (PlanetIndex, bool) {
+	for planet := Sun; planet <= Neptune; planet++ {
+		body := countdown.solarSystem.planets[planet]
+		if body.position.Sub(position).Norm() <= body.radius {
+			return planet, true
+		}
+	}
+	return 0, false
+}
+
+// ZIndex of the countdown
+func (countdown *DrawCountdown) ZIndex() ZIndex {
+	return countdown.zindex
+}
+
+// Animate countdown to the time left now
+func (countdown *DrawCountdown) Animate(dt float64) bool {
+	countdown.time += dt
+
+	now := time.Now()
+	state := countdown.solarSystem.Countdown()
+	countdown.fraction = state.Fraction(now)
+	countdown.alert, countdown.alerting = state.alerting(now)
+
+	if countdown.alerting && !countdown.rippled {
+		countdown.solarSystem.AddDrawable(NewRipple(countdown.solarSystem.planets[Sun].position, 60, math.Hypot(WallWidth, WallHeight), 8, color.RGBA{R: 255, G: 40, B: 0, A: 255}))
+	}
+	countdown.rippled = countdown.alerting
+
+	return true
+}
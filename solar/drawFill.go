@@ -0,0 +1,42 @@
+package solar
+
+import (
+	"github.com/golang/geo/r2"
+)
+
+// DrawFill renders every led in one color
+type DrawFill struct {
+
+	// color of the wall, alpha lets what is below show through
+	color RGBA
+
+	// z position of the fill
+	zindex ZIndex
+}
+
+var _ Drawable = &DrawFill{}
+
+// NewFill Construct a fill of color
+func NewFill(color RGBA) *DrawFill {
+	return &DrawFill{color: color}
+}
+
+// Affects every planet
+func (fill *DrawFill) Affects(position r2.Point, radius float64) bool {
+	return true
+}
+
+// ColorAt Returns the color at position blended on top of baseColor
+func (fill *DrawFill) ColorAt(position r2.Point, baseColor RGBA) (color RGBA) {
+	return fill.color.BlendWith(baseColor)
+}
+
+// ZIndex of the fill
+func (fill *DrawFill) ZIndex() ZIndex {
+	return fill.zindex
+}
+
+// Animate fill
+func (fill *DrawFill) Animate(dt float64) bool {
+	return true
+}
@@ -3,11 +3,30 @@ package solar
 import (
 	"image/color"
 	"math"
+	"math/rand"
 
 	"github.com/golang/geo/r2"
 )
 
-// DrawLine renders a line that moves back and forth
+// LineMode how a DrawLine moves between its start and end
+type LineMode int
+
+// Line movement modes
+const (
+	// jump back to the start after reaching the end
+	LineWrap LineMode = iota
+
+	// reverse direction at each end
+	LinePingPong
+
+	// travel to the end once then remove the line
+	LineOnce
+
+	// wander back and forth at a randomly changing speed
+	LineRandomWalk
+)
+
+// DrawLine renders a line that moves across the wall from start to end, repeating according to its mode
 type DrawLine struct {
 
 	// where line starts
@@ -16,7 +35,7 @@ type DrawLine struct {
 	// where line neds
 	endPosition r2.Point
 
-	// time it takes for line to travel from start to end
+	// time it takes for line to travel from start to end, 0 if the line is moved by SetProgress instead
 	traverseTime float64
 
 	// position of the line along movement vector, 0 to 1
@@ -31,8 +50,26 @@ type DrawLine struct {
 	// color of the line
 	color color.RGBA
 
+	// colors the line from a palette instead of color when set, distance is measured from the start
+	paint *Paint
+
+	// seconds since the line started, for paint sampled by time
+	time float64
+
 	// z position of ball
 	zindex ZIndex
+
+	// how the line repeats its movement
+	mode LineMode
+
+	// seconds to fade in after leaving the start and fade out before reaching the end, 0 for no fade
+	fadeTime float64
+
+	// -1 while a ping pong line is heading back to the start
+	heading float64
+
+	// speed of a random walk in fractions of the path per second
+	walkVelocity float64
 }
 
 var _ Drawable = &DrawLine{}
@@ -66,7 +103,12 @@ func (line *DrawLine) ColorAt(position r2.Point, baseColor RGBA) (color RGBA) {
 		return baseColor
 	}
 	distance = distance / line.lineWidth
-	color = RGBA{line.color.R, line.color.G, line.color.B, uint8((1.0 - distance) * 255.0)}
+	color = RGBA(line.color)
+	if line.paint != nil {
+		offset := position.Sub(line.startPosition)
+		color = line.paint.At(line.time, offset.Norm(), math.Atan2(offset.Y, offset.X))
+	}
+	color.A = uint8((1.0 - distance) * line.fade() * 255.0)
 
 	result := color.BlendWith(baseColor)
 
@@ -85,21 +127,98 @@ func (line *DrawLine) ZIndex() ZIndex {
 	return line.zindex
 }
 
+// SetProgress place the line a fraction of the way from start to end, 0 to 1
+func (line *DrawLine) SetProgress(progress float64) {
+	line.currentPosition = line.endPosition.Sub(line.startPosition).Mul(progress).Add(line.startPosition)
+}
+
+// SetColor change the color of the line
+func (line *DrawLine) SetColor(newColor RGBA) {
+	line.color = color.RGBA(newColor)
+}
+
+// SetPaint color the line from a palette, nil to use its color
+func (line *DrawLine) SetPaint(paint *Paint) {
+	line.paint = paint
+}
+
+// SetMode change how the line repeats, with fadeTime seconds of fade at each end of a wrap or once
+func (line *DrawLine) SetMode(mode LineMode, fadeTime float64) {
+	line.mode = mode
+	line.fadeTime = fadeTime
+}
+
+// progress how far along the line is from start to end, 0 to 1
+func (line *DrawLine) progress() float64 {
+	totalDistance := line.endPosition.Sub(line.startPosition).Norm()
+	if totalDistance == 0 {
+		return 0
+	}
+	return line.currentPosition.Sub(line.startPosition).Norm() / totalDistance
+}
+
+// fade multiplier for the opacity of a line near the ends of its path, so wrapping is not a visible jump
+func (line *DrawLine) fade() float64 {
+	if line.fadeTime <= 0 || line.traverseTime <= 0 || (line.mode != LineWrap && line.mode != LineOnce) {
+		return 1.0
+	}
+
+	progress := line.progress()
+	secondsFromEnd := math.Min(progress, 1-progress) * line.traverseTime
+	return math.Min(1.0, secondsFromEnd/line.fadeTime)
+}
+
+// SetWidth change the width of the line
+func (line *DrawLine) SetWidth(width float64) {
+	line.lineWidth = width
+}
+
 // Animate circle
 func (line *DrawLine) Animate(dt float64) bool {
-	totalDistance := line.endPosition.Sub(line.startPosition).Norm()
-	distance := line.currentPosition.Sub(line.startPosition).Norm()
+	line.time += dt
+	if line.traverseTime <= 0 {
+		return true
+	}
 
-	distance += (totalDistance / line.traverseTime) * dt
-	//fmt.Println(distance, totalDistance, line.traverseTime)
-	if distance > totalDistance {
-		distance = 0.0
+	progress := line.progress()
+	step := dt / line.traverseTime
+	keepAlive := true
+
+	switch line.mode {
+	case LineWrap:
+		progress += step
+		if progress > 1 {
+			progress = 0.0
+		}
+	case LinePingPong:
+		if line.heading == 0 {
+			line.heading = 1
+		}
+		progress += step * line.heading
+		if progress > 1 {
+			progress, line.heading = 2-progress, -1
+		} else if progress < 0 {
+			progress, line.heading = -progress, 1
+		}
+	case LineOnce:
+		progress += step
+		if progress >= 1 {
+			progress, keepAlive = 1, false
+		}
+	case LineRandomWalk:
+		// nudge the speed randomly, never faster than a normal traverse
+		line.walkVelocity += rand.NormFloat64() * 2 * dt / line.traverseTime
+		line.walkVelocity = math.Max(-1/line.traverseTime, math.Min(1/line.traverseTime, line.walkVelocity))
+		progress += line.walkVelocity * dt
+		if progress > 1 || progress < 0 {
+			line.walkVelocity = -line.walkVelocity
+			progress = math.Max(0, math.Min(1, progress))
+		}
 	}
 
-	moveDir := line.endPosition.Sub(line.startPosition).Normalize()
-	line.currentPosition = moveDir.Mul(distance).Add(line.startPosition)
+	line.SetProgress(progress)
 
 	//fmt.Println("Animated to ", line.currentPosition, dt)
 
-	return true
+	return keepAlive
 }
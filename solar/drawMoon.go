@@ -0,0 +1,88 @@
+package solar
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/golang/geo/r2"
+)
+
+// DrawMoon renders the phase of the moon on a body, lighting the fraction of its leds that matches the lit fraction of the moon
+// The lit side is on the right while waxing and the left while waning, flipped for walls in the southern hemisphere
+type DrawMoon struct {
+
+	// system the simulation clock and location are read from
+	solarSystem *System
+
+	// center and radius of the body the phase is drawn on
+	center r2.Point
+	radius float64
+
+	// where the moon is in its month, 0 at new moon through 0.5 at full back to 1
+	age float64
+
+	// fraction of the moon that is lit
+	illuminated float64
+
+	// color of the lit part, the dark part is a faint glow of the same color
+	color color.RGBA
+
+	// z position of moon
+	zindex ZIndex
+}
+
+var _ Drawable = &DrawMoon{}
+
+// NewMoon Construct the moon phase drawn on planet, usually Earth
+func NewMoon(planet PlanetIndex, solarSystem *System) *DrawMoon {
+	body := solarSystem.planets[planet]
+	moon := &DrawMoon{
+		solarSystem: solarSystem,
+		center:      body.position,
+		radius:      body.radius,
+		color:       color.RGBA{R: 230, G: 230, B: 210, A: 255},
+		zindex:      1,
+	}
+	moon.age, moon.illuminated = MoonPhase(solarSystem.Clock().Now())
+	return moon
+}
+
+// Affects returns bounding circle check against the body
+func (moon *DrawMoon) Affects(position r2.Point, radius float64) bool {
+	return moon.center.Sub(position).Norm() < radius+moon.radius
+}
+
+// ColorAt Returns the color at position blended on top of baseColor
+func (moon *DrawMoon) ColorAt(position r2.Point, baseColor RGBA) (color RGBA) {
+	offset := position.Sub(moon.center)
+	if offset.Norm() > moon.radius {
+		return baseColor
+	}
+
+	litDirection := 0.0
+	if moon.age > 0.5 {
+		litDirection = math.Pi
+	}
+	if location := moon.solarSystem.location; location != nil && location.Latitude < 0 {
+		litDirection = math.Pi - litDirection
+	}
+
+	// the lit arc covers the lit fraction of the ring, with a soft edge about one led wide
+	halfArc := math.Pi * moon.illuminated
+	fromLit := math.Abs(math.Remainder(math.Atan2(offset.Y, offset.X)-litDirection, 2*math.Pi))
+	light := math.Max(0.06, math.Min(1, (halfArc-fromLit)/0.3+0.5))
+
+	color = RGBA{moon.color.R, moon.color.G, moon.color.B, uint8(light * float64(moon.color.A))}
+	return color.BlendWith(baseColor)
+}
+
+// ZIndex of the moon
+func (moon *DrawMoon) ZIndex() ZIndex {
+	return moon.zindex
+}
+
+// Animate moon to the phase at the simulation time
+func (moon *DrawMoon) Animate(dt float64) bool {
+	moon.age, moon.illuminated = MoonPhase(moon.solarSystem.Clock().Now())
+	return true
+}
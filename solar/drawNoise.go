@@ -0,0 +1,100 @@
+package solar
+
+import (
+	"math"
+
+	"github.com/golang/geo/r2"
+)
+
+// DrawNoise renders slowly shifting color over the whole wall from simplex noise, with time as the third dimension
+// Noise is sampled at each led's position on the wall, so nearby planets share coherent color
+type DrawNoise struct {
+
+	// seconds since the noise started
+	time float64
+
+	// noise features per unit of wall distance, smaller is larger blobs of color
+	scale float64
+
+	// how fast the noise changes, in noise units per second
+	speed float64
+
+	// layers of finer detail added on top of the base noise
+	octaves int
+
+	// colors the noise maps onto, from the lowest to the highest values
+	palette *Palette
+
+	// how strongly the noise covers what is below, 0 to 1
+	opacity float64
+
+	// z position of the noise
+	zindex ZIndex
+}
+
+var _ Drawable = &DrawNoise{}
+
+// NewNoise Construct a plasma of slowly drifting color through palette
+func NewNoise(palette *Palette) *DrawNoise {
+	return &DrawNoise{
+		scale:   0.02,
+		speed:   0.08,
+		octaves: 2,
+		palette: palette,
+		opacity: 1.0,
+		zindex:  0,
+	}
+}
+
+// SetScale noise features per unit of wall distance
+func (noise *DrawNoise) SetScale(scale float64) {
+	noise.scale = scale
+}
+
+// SetSpeed noise units per second the field changes
+func (noise *DrawNoise) SetSpeed(speed float64) {
+	noise.speed = speed
+}
+
+// SetOctaves layers of detail, at least 1
+func (noise *DrawNoise) SetOctaves(octaves int) {
+	if octaves < 1 {
+		octaves = 1
+	}
+	noise.octaves = octaves
+}
+
+// SetPalette colors the noise maps onto
+func (noise *DrawNoise) SetPalette(palette *Palette) {
+	noise.palette = palette
+}
+
+// SetOpacity how strongly the noise covers what is below, 0 to 1
+func (noise *DrawNoise) SetOpacity(opacity float64) {
+	noise.opacity = math.Max(0, math.Min(1, opacity))
+}
+
+// Affects every planet
+func (noise *DrawNoise) Affects(position r2.Point, radius float64) bool {
+	return noise.opacity > 0
+}
+
+// ColorAt Returns the color at position blended on top of baseColor
+func (noise *DrawNoise) ColorAt(position r2.Point, baseColor RGBA) (color RGBA) {
+	value := FractalNoise3(position.X*noise.scale, position.Y*noise.scale, noise.time*noise.speed, noise.octaves)
+
+	color = noise.palette.At((value + 1) / 2)
+	color.A = uint8(float64(color.A) * noise.opacity)
+	return color.BlendWith(baseColor)
+}
+
+// ZIndex of the noise
+func (noise *DrawNoise) ZIndex() ZIndex {
+	return noise.zindex
+}
+
+// Animate noise
+func (noise *DrawNoise) Animate(dt float64) bool {
+	noise.time += dt
+	return true
+}
@@ -0,0 +1,264 @@
+package solar
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/golang/geo/r2"
+)
+
+// DrawParticles renders a particle emitter, each particle a soft disc that moves, falls toward an attractor and changes over its life
+type DrawParticles struct {
+
+	// where particles are born
+	position r2.Point
+
+	// particles are born anywhere within this distance of position
+	emitRadius float64
+
+	// particles born per second
+	rate float64
+
+	// seconds left to emit, negative emits forever
+	emitTime float64
+
+	// fraction of a particle owed to the next frame
+	owed float64
+
+	// seconds a particle lives, plus or minus lifeSpread
+	life       float64
+	lifeSpread float64
+
+	// radians direction particles are launched in, plus or minus spread
+	angle  float64
+	spread float64
+
+	// distance per second particles are launched at, plus or minus speedSpread
+	speed       float64
+	speedSpread float64
+
+	// point particles fall toward and its acceleration in distance per second squared, no gravity when 0
+	attractor r2.Point
+	gravity   float64
+
+	// color of a particle over its life, timeline runs from 0 at birth to 1 at death
+	colorOverLife *Timeline
+
+	// radius of a particle over its life, timeline runs from 0 at birth to 1 at death
+	sizeOverLife *Timeline
+
+	// live particles
+	particles []particle
+
+	// bounding box of the live particles including their size, used by Affects
+	boundsMin r2.Point
+	boundsMax r2.Point
+
+	// z position of particles
+	zindex ZIndex
+}
+
+// particle one live particle of a DrawParticles
+type particle struct {
+	position r2.Point
+	velocity r2.Point
+
+	// seconds since birth and seconds it lives
+	age  float64
+	life float64
+
+	// current color and radius, from the emitter timelines
+	color  RGBA
+	radius float64
+}
+
+var _ Drawable = &DrawParticles{}
+
+// NewParticles Construct an emitter at position that emits forever, configure it with the chainable setters
+func NewParticles(position r2.Point) *DrawParticles {
+	return &DrawParticles{
+		position: position,
+		rate:     20,
+		emitTime: -1,
+		life:     2,
+		spread:   math.Pi,
+		speed:    10,
+		colorOverLife: NewTimeline(
+			Keyframe{Time: 0, Color: RGBA{R: 255, G: 255, B: 255, A: 255}},
+			Keyframe{Time: 1, Color: RGBA{R: 255, G: 255, B: 255, A: 0}},
+		),
+		sizeOverLife: NewTimeline(
+			Keyframe{Time: 0, Value: 2},
+			Keyframe{Time: 1, Value: 2},
+		),
+		zindex: 3,
+	}
+}
+
+// Rate emit perSecond particles every second from anywhere within radius of the emitter
+func (emitter *DrawParticles) Rate(perSecond float64, radius float64) *DrawParticles {
+	emitter.rate = perSecond
+	emitter.emitRadius = radius
+	return emitter
+}
+
+// For stop emitting after seconds, the emitter is removed once its last particle dies
+func (emitter *DrawParticles) For(seconds float64) *DrawParticles {
+	emitter.emitTime = seconds
+	return emitter
+}
+
+// Burst emit count particles at once, using the settings made so far
+func (emitter *DrawParticles) Burst(count int) *DrawParticles {
+	for i := 0; i < count; i++ {
+		emitter.emit()
+	}
+	emitter.updateBounds()
+	return emitter
+}
+
+// Life particles live seconds, plus or minus spread
+func (emitter *DrawParticles) Life(seconds float64, spread float64) *DrawParticles {
+	emitter.life = seconds
+	emitter.lifeSpread = spread
+	return emitter
+}
+
+// Velocity launch particles in direction angle plus or minus spread radians, at speed plus or minus speedSpread
+func (emitter *DrawParticles) Velocity(angle float64, spread float64, speed float64, speedSpread float64) *DrawParticles {
+	emitter.angle = angle
+	emitter.spread = spread
+	emitter.speed = speed
+	emitter.speedSpread = speedSpread
+	return emitter
+}
+
+// Gravity pull particles toward point with acceleration strength, negative pushes them away
+func (emitter *DrawParticles) Gravity(point r2.Point, strength float64) *DrawParticles {
+	emitter.attractor = point
+	emitter.gravity = strength
+	return emitter
+}
+
+// ColorOverLife color particles from timeline, where time 0 is birth and 1 is death
+func (emitter *DrawParticles) ColorOverLife(timeline *Timeline) *DrawParticles {
+	emitter.colorOverLife = timeline
+	return emitter
+}
+
+// SizeOverLife size particles from timeline, where time 0 is birth and 1 is death
+func (emitter *DrawParticles) SizeOverLife(timeline *Timeline) *DrawParticles {
+	emitter.sizeOverLife = timeline
+	return emitter
+}
+
+// SetPosition move the emitter, live particles are left where they are
+func (emitter *DrawParticles) SetPosition(position r2.Point) {
+	emitter.position = position
+}
+
+// Affects returns bounding box check against the live particles
+func (emitter *DrawParticles) Affects(position r2.Point, radius float64) bool {
+	if len(emitter.particles) == 0 {
+		return false
+	}
+	return position.X+radius > emitter.boundsMin.X && position.X-radius < emitter.boundsMax.X &&
+		position.Y+radius > emitter.boundsMin.Y && position.Y-radius < emitter.boundsMax.Y
+}
+
+// ColorAt Returns the color at position blended on top of baseColor
+func (emitter *DrawParticles) ColorAt(position r2.Point, baseColor RGBA) (color RGBA) {
+	for i := range emitter.particles {
+		particle := &emitter.particles[i]
+		distance := position.Sub(particle.position).Norm()
+		if distance >= particle.radius {
+			continue
+		}
+
+		falloff := 1 - distance/particle.radius
+		color = RGBA{particle.color.R, particle.color.G, particle.color.B, uint8(falloff * falloff * float64(particle.color.A))}
+		baseColor = color.BlendWith(baseColor)
+	}
+	return baseColor
+}
+
+// ZIndex of the particles
+func (emitter *DrawParticles) ZIndex() ZIndex {
+	return emitter.zindex
+}
+
+// Animate particles, emitting new ones and removing dead ones
+func (emitter *DrawParticles) Animate(dt float64) bool {
+	live := emitter.particles[:0]
+	for _, particle := range emitter.particles {
+		particle.age += dt
+		if particle.age >= particle.life {
+			continue
+		}
+
+		if emitter.gravity != 0 {
+			toward := emitter.attractor.Sub(particle.position)
+			if distance := toward.Norm(); distance > 0.5 {
+				particle.velocity = particle.velocity.Add(toward.Mul(emitter.gravity * dt / distance))
+			}
+		}
+		particle.position = particle.position.Add(particle.velocity.Mul(dt))
+		emitter.style(&particle)
+		live = append(live, particle)
+	}
+	emitter.particles = live
+
+	if emitter.emitTime != 0 {
+		emitter.owed += emitter.rate * dt
+		for ; emitter.owed >= 1; emitter.owed-- {
+			emitter.emit()
+		}
+		if emitter.emitTime > 0 {
+			emitter.emitTime = math.Max(0, emitter.emitTime-dt)
+		}
+	}
+
+	emitter.updateBounds()
+
+	return emitter.emitTime != 0 || len(emitter.particles) > 0
+}
+
+// emit a new particle
+func (emitter *DrawParticles) emit() {
+	offsetAngle := rand.Float64() * 2 * math.Pi
+	offset := r2.Point{X: math.Cos(offsetAngle), Y: math.Sin(offsetAngle)}.Mul(emitter.emitRadius * math.Sqrt(rand.Float64()))
+
+	angle := emitter.angle + (rand.Float64()*2-1)*emitter.spread
+	speed := emitter.speed + (rand.Float64()*2-1)*emitter.speedSpread
+
+	particle := particle{
+		position: emitter.position.Add(offset),
+		velocity: r2.Point{X: math.Cos(angle), Y: math.Sin(angle)}.Mul(speed),
+		life:     math.Max(0.05, emitter.life+(rand.Float64()*2-1)*emitter.lifeSpread),
+	}
+	emitter.style(&particle)
+	emitter.particles = append(emitter.particles, particle)
+}
+
+// style set the color and size of particle for its age
+func (emitter *DrawParticles) style(particle *particle) {
+	lifeFraction := particle.age / particle.life
+	particle.color = emitter.colorOverLife.Color(lifeFraction)
+	particle.radius = math.Max(0, emitter.sizeOverLife.Value(lifeFraction))
+}
+
+// updateBounds recompute the box around every live particle
+func (emitter *DrawParticles) updateBounds() {
+	if len(emitter.particles) == 0 {
+		return
+	}
+
+	emitter.boundsMin = r2.Point{X: math.Inf(1), Y: math.Inf(1)}
+	emitter.boundsMax = r2.Point{X: math.Inf(-1), Y: math.Inf(-1)}
+	for _, particle := range emitter.particles {
+		emitter.boundsMin.X = math.Min(emitter.boundsMin.X, particle.position.X-particle.radius)
+		emitter.boundsMin.Y = math.Min(emitter.boundsMin.Y, particle.position.Y-particle.radius)
+		emitter.boundsMax.X = math.Max(emitter.boundsMax.X, particle.position.X+particle.radius)
+		emitter.boundsMax.Y = math.Max(emitter.boundsMax.Y, particle.position.Y+particle.radius)
+	}
+}
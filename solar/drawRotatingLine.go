@@ -19,7 +19,7 @@ type DrawRotatingLine struct {
 	// where line neds
 	length float64
 
-	// time it takes for line to travel from start to end
+	// time it takes for line to make a full turn, 0 if the angle is set by SetAngle instead
 	traverseTime float64
 
 	// angle of the line
@@ -31,6 +31,12 @@ type DrawRotatingLine struct {
 	// color of the line
 	color color.RGBA
 
+	// colors the line from a palette instead of color when set, angle is the direction of the line
+	paint *Paint
+
+	// seconds since the line started, for paint sampled by time
+	time float64
+
 	// z position of line
 	zindex ZIndex
 }
@@ -70,10 +76,11 @@ func (line *DrawRotatingLine) ColorAt(position r2.Point, baseColor RGBA) (color
 		return baseColor
 	}
 	distance = distance / line.lineWidth
-	color = RGBA{line.color.R, line.color.G, line.color.B, uint8((1.0 - distance) * 255.0)}
-	if (line.currentAngle < 0.5) {
-		color = RGBA{255,0,0, uint8((1.0 - distance) * 255.0)}
-	} 
+	color = RGBA(line.color)
+	if line.paint != nil {
+		color = line.paint.At(line.time, position.Sub(line.startPosition).Norm(), line.currentAngle)
+	}
+	color.A = uint8((1.0 - distance) * 255.0)
 
 	result := color.BlendWith(baseColor)
 
@@ -110,11 +117,29 @@ func (line *DrawRotatingLine) ZIndex() ZIndex {
 	return line.zindex
 }
 
+// SetAngle point the line at angle radians, only useful when traverseTime is 0 so Animate does not turn it
+func (line *DrawRotatingLine) SetAngle(angle float64) {
+	line.currentAngle = angle
+}
+
+// SetColor change the color of the line
+func (line *DrawRotatingLine) SetColor(newColor RGBA) {
+	line.color = color.RGBA(newColor)
+}
+
+// SetPaint color the line from a palette, nil to use its color
+func (line *DrawRotatingLine) SetPaint(paint *Paint) {
+	line.paint = paint
+}
+
 // Animate circle
 func (line *DrawRotatingLine) Animate(dt float64) bool {
-	line.currentAngle += 2 * math.Pi * dt / line.traverseTime
-	if line.currentAngle > 2*math.Pi {
-		line.currentAngle -= 2 * math.Pi
+	line.time += dt
+	if line.traverseTime > 0 {
+		line.currentAngle += 2 * math.Pi * dt / line.traverseTime
+		if line.currentAngle > 2*math.Pi {
+			line.currentAngle -= 2 * math.Pi
+		}
 	}
 
 	endOffset := r2.Point{X: math.Cos(line.currentAngle) * line.length, Y: math.Sin(line.currentAngle) * line.length}
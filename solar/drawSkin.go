@@ -0,0 +1,200 @@
+package solar
+
+import (
+	"math"
+
+	"github.com/golang/geo/r2"
+)
+
+// skin textures and rotation periods in seconds of the bodies that have one
+var skins = map[PlanetIndex]struct {
+	texture        skinTexture
+	rotationPeriod float64
+}{
+	Earth:   {earthTexture, 23.934 * 3600},
+	Mars:    {marsTexture, 24.623 * 3600},
+	Jupiter: {jupiterTexture, 9.925 * 3600},
+	Saturn:  {saturnTexture, 10.56 * 3600},
+}
+
+// skinTexture color of the led at angle radians around the body, where 0 points right along the wall, alpha is ignored
+type skinTexture func(skin *DrawSkin, angle float64) RGBA
+
+// DrawSkin renders the surface of one body on its ring of leds, such as Jupiter's bands or Earth's day and night
+type DrawSkin struct {
+
+	// body the skin is drawn on
+	planet PlanetIndex
+
+	// center and radius of the body
+	center r2.Point
+	radius float64
+
+	// where the sun is, for bodies lit from one side
+	sun r2.Point
+
+	// clock the body turns with
+	clock *SimulationClock
+
+	// seconds the skin has been shown, for effects that move in real time
+	time float64
+
+	// simulated seconds for one turn of the body
+	rotationPeriod float64
+
+	// color of each led
+	texture skinTexture
+
+	// how strongly the skin covers what is below, 0 to 255
+	opacity uint8
+
+	// z position of skin
+	zindex ZIndex
+}
+
+var _ Drawable = &DrawSkin{}
+
+// NewSkin Construct the skin of planet, false if the body has no skin
+func NewSkin(planet PlanetIndex, solarSystem *System) (*DrawSkin, bool) {
+	skin, ok := skins[planet]
+	if !ok {
+		return nil, false
+	}
+
+	body := solarSystem.planets[planet]
+	return &DrawSkin{
+		planet:         planet,
+		center:         body.position,
+		radius:         body.radius,
+		sun:            solarSystem.planets[Sun].position,
+		clock:          solarSystem.Clock(),
+		rotationPeriod: skin.rotationPeriod,
+		texture:        skin.texture,
+		opacity:        255,
+		zindex:         1,
+	}, true
+}
+
+// SetRotationPeriod change the simulated seconds for one turn of the body
+func (skin *DrawSkin) SetRotationPeriod(seconds float64) {
+	skin.rotationPeriod = seconds
+}
+
+// Affects returns bounding circle check against the body
+func (skin *DrawSkin) Affects(position r2.Point, radius float64) bool {
+	return skin.center.Sub(position).Norm() < radius+skin.radius
+}
+
+// ColorAt Returns the color at position blended on top of baseColor
+func (skin *DrawSkin) ColorAt(position r2.Point, baseColor RGBA) (color RGBA) {
+	offset := position.Sub(skin.center)
+	if offset.Norm() > skin.radius {
+		return baseColor
+	}
+
+	color = skin.texture(skin, math.Atan2(offset.Y, offset.X))
+	color.A = skin.opacity
+	return color.BlendWith(baseColor)
+}
+
+// ZIndex of the skin
+func (skin *DrawSkin) ZIndex() ZIndex {
+	return skin.zindex
+}
+
+// Animate skin
+func (skin *DrawSkin) Animate(dt float64) bool {
+	skin.time += dt
+	return true
+}
+
+// rotation radians the body has turned at the simulation time
+func (skin *DrawSkin) rotation() float64 {
+	if skin.rotationPeriod <= 0 {
+		return 0
+	}
+	seconds := float64(skin.clock.Now().UnixNano()) / 1e9
+	return 2 * math.Pi * math.Mod(seconds/skin.rotationPeriod, 1)
+}
+
+// onSurface where the led at angle on the rim sees the surface, the latitude from its height and the longitude facing it
+// Leds on the left and right of the ring see the edges of the disc, so features pass them as the body turns
+func (skin *DrawSkin) onSurface(angle float64) (latitude float64, longitude float64) {
+	latitude = -math.Asin(math.Sin(angle)) // y grows down the wall
+	longitude = skin.rotation() + math.Pi/2
+	if math.Cos(angle) < 0 {
+		longitude -= math.Pi
+	}
+	return latitude, longitude
+}
+
+// jupiterBands colors from the equator to the poles
+var jupiterBands = NewPalette(BlendOKLab,
+	RGBA{R: 240, G: 220, B: 180, A: 255},
+	RGBA{R: 170, G: 110, B: 60, A: 255},
+	RGBA{R: 235, G: 210, B: 170, A: 255},
+	RGBA{R: 150, G: 95, B: 55, A: 255},
+	RGBA{R: 200, G: 180, B: 150, A: 255},
+)
+
+// jupiterTexture cream and brown bands that churn slowly, with the Great Red Spot passing the edges once a turn
+func jupiterTexture(skin *DrawSkin, angle float64) RGBA {
+	latitude, longitude := skin.onSurface(angle)
+
+	// bands wobble with the turning surface
+	churn := SimplexNoise3(math.Cos(longitude)*2, math.Sin(longitude)*2, latitude*3) * 0.06
+	color := jupiterBands.At(math.Abs(latitude)/(math.Pi/2) + churn)
+
+	// the spot sits 22 degrees south and fades in as it turns to face the led
+	const spotLatitude, spotSize = -22 * math.Pi / 180, 0.45
+	spotDistance := math.Hypot(latitude-spotLatitude, math.Remainder(longitude, 2*math.Pi))
+	if spotDistance < spotSize {
+		spot := RGBA{R: 200, G: 60, B: 30, A: uint8(255 * (1 - spotDistance/spotSize))}
+		color = spot.BlendWith(color)
+	}
+	return color
+}
+
+// saturnTexture pale gold, with the rings shimmering where their tilted plane crosses the body
+func saturnTexture(skin *DrawSkin, angle float64) RGBA {
+	color := RGBA{R: 210, G: 185, B: 120, A: 255}
+
+	// the ring plane crosses the rim at the tilt and opposite it
+	const ringTilt, ringWidth = -0.45, 0.5
+	fromRing := math.Abs(math.Remainder(angle-ringTilt, math.Pi))
+	if fromRing < ringWidth {
+		shimmer := 0.6 + 0.4*SimplexNoise3(math.Cos(angle)*3, math.Sin(angle)*3, skin.time*0.7)
+		ring := RGBA{R: 255, G: 235, B: 180, A: uint8(255 * shimmer * (1 - fromRing/ringWidth))}
+		color = ring.BlendWith(color)
+	}
+	return color
+}
+
+// earthTexture oceans and continents turning with the day, dark on the side facing away from the sun
+func earthTexture(skin *DrawSkin, angle float64) RGBA {
+	latitude, longitude := skin.onSurface(angle)
+
+	color := RGBA{R: 10, G: 70, B: 200, A: 255}
+	if land := SimplexNoise3(math.Cos(longitude)*1.5, math.Sin(longitude)*1.5, latitude*2); land > 0.15 {
+		color = RGBA{R: 40, G: 150, B: 50, A: 255}
+	}
+	if math.Abs(latitude) > 1.2 {
+		color = RGBA{R: 230, G: 240, B: 255, A: 255}
+	}
+
+	// the terminator is soft, with a short dusk around it
+	toSun := skin.sun.Sub(skin.center)
+	facing := math.Cos(angle - math.Atan2(toSun.Y, toSun.X))
+	daylight := math.Max(0.08, math.Min(1, facing*3+0.5))
+	return RGBA{R: uint8(float64(color.R) * daylight), G: uint8(float64(color.G) * daylight), B: uint8(float64(color.B) * daylight), A: 255}
+}
+
+// marsTexture dusty red with darker patches and drifting dust
+func marsTexture(skin *DrawSkin, angle float64) RGBA {
+	latitude, longitude := skin.onSurface(angle)
+
+	dark := SimplexNoise3(math.Cos(longitude)*2, math.Sin(longitude)*2, latitude*2)
+	dust := SimplexNoise3(math.Cos(angle)*1.5, math.Sin(angle)*1.5, skin.time*0.05)
+	shade := math.Max(0.5, math.Min(1.1, 0.85-dark*0.25+dust*0.15))
+	return RGBA{R: clampByte(190 * shade), G: clampByte(80 * shade), B: clampByte(40 * shade), A: 255}
+}
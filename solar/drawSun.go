@@ -0,0 +1,139 @@
+package solar
+
+import (
+	"image/color"
+	"math"
+	"math/rand"
+
+	"github.com/golang/geo/r2"
+)
+
+// average seconds between flares at an intensity of 1
+const flareInterval = 20.0
+
+// DrawSun renders a flickering corona on the Sun, with flares that erupt and throw particles and a ripple across the wall
+type DrawSun struct {
+
+	// system flares are added to and the intensity is read from
+	solarSystem *System
+
+	// center and radius of the sun
+	center r2.Point
+	radius float64
+
+	// seconds since the sun started
+	time float64
+
+	// how lively the sun is, 1 is normal, updated each frame from the system
+	intensity float64
+
+	// seconds until the next flare, counted down faster when the sun is more active
+	untilFlare float64
+
+	// flares still brightening the corona
+	flares []sunFlare
+
+	// z position of sun
+	zindex ZIndex
+}
+
+// sunFlare one flare brightening the corona around its angle
+type sunFlare struct {
+	angle float64
+	age   float64
+	life  float64
+}
+
+var _ Drawable = &DrawSun{}
+
+// NewSun Construct the corona and flares of the Sun
+func NewSun(solarSystem *System) *DrawSun {
+	sun := solarSystem.planets[Sun]
+	return &DrawSun{
+		solarSystem: solarSystem,
+		center:      sun.position,
+		radius:      sun.radius,
+		intensity:   solarSystem.SunIntensity(solarSystem.Clock().Now()),
+		untilFlare:  flareInterval * rand.ExpFloat64(),
+		zindex:      1,
+	}
+}
+
+// Affects returns bounding circle check against the sun
+func (sun *DrawSun) Affects(position r2.Point, radius float64) bool {
+	return sun.center.Sub(position).Norm() < radius+sun.radius
+}
+
+// ColorAt Returns the color at position blended on top of baseColor
+func (sun *DrawSun) ColorAt(position r2.Point, baseColor RGBA) (color RGBA) {
+	offset := position.Sub(sun.center)
+	if offset.Norm() > sun.radius {
+		return baseColor
+	}
+	angle := math.Atan2(offset.Y, offset.X)
+
+	// the corona churns faster and flickers harder on an active sun
+	activity := math.Min(sun.intensity, 2)
+	flicker := FractalNoise3(math.Cos(angle)*1.5, math.Sin(angle)*1.5, sun.time*(0.3+0.5*activity), 2)
+	heat := 0.65 + flicker*(0.1+0.2*activity)
+
+	for _, flare := range sun.flares {
+		spread := math.Remainder(angle-flare.angle, 2*math.Pi) / 0.4
+		heat += math.Exp(-spread*spread) * math.Sin(math.Pi*flare.age/flare.life) * 0.5
+	}
+
+	color = Palettes["fire"].At(math.Min(1, heat))
+	return color.BlendWith(baseColor)
+}
+
+// ZIndex of the sun
+func (sun *DrawSun) ZIndex() ZIndex {
+	return sun.zindex
+}
+
+// Animate corona and erupt flares
+func (sun *DrawSun) Animate(dt float64) bool {
+	sun.time += dt
+	sun.intensity = sun.solarSystem.SunIntensity(sun.solarSystem.Clock().Now())
+
+	live := sun.flares[:0]
+	for _, flare := range sun.flares {
+		flare.age += dt
+		if flare.age < flare.life {
+			live = append(live, flare)
+		}
+	}
+	sun.flares = live
+
+	sun.untilFlare -= dt * sun.intensity
+	if sun.untilFlare <= 0 {
+		sun.untilFlare = flareInterval * rand.ExpFloat64()
+		sun.erupt(math.Atan2(WallHeight, WallWidth) + (rand.Float64()-0.5)*math.Pi*0.8)
+	}
+
+	return true
+}
+
+// erupt a flare at angle, throwing particles that way and a ripple across the wall
+func (sun *DrawSun) erupt(angle float64) {
+	strength := math.Min(sun.intensity, 2)
+	sun.flares = append(sun.flares, sunFlare{angle: angle, life: 2 + strength})
+
+	edge := sun.center.Add(r2.Point{X: math.Cos(angle), Y: math.Sin(angle)}.Mul(sun.radius / 2))
+	sun.solarSystem.AddDrawable(NewParticles(edge).
+		For(0).
+		Life(4, 1.5).
+		Velocity(angle, 0.25, 30+15*strength, 10).
+		ColorOverLife(NewTimeline(
+			Keyframe{Time: 0, Color: RGBA{R: 255, G: 250, B: 200, A: 255}},
+			Keyframe{Time: 0.3, Color: RGBA{R: 255, G: 140, B: 20, A: 220}},
+			Keyframe{Time: 1, Color: RGBA{R: 200, G: 30, B: 0, A: 0}},
+		)).
+		SizeOverLife(NewTimeline(
+			Keyframe{Time: 0, Value: 3},
+			Keyframe{Time: 1, Value: 6},
+		)).
+		Burst(int(10 + 15*strength)))
+
+	sun.solarSystem.AddDrawable(NewRipple(sun.center, 40, math.Hypot(WallWidth, WallHeight), 5, color.RGBA{R: 255, G: 160, B: 40, A: uint8(80 + 50*strength)}))
+}
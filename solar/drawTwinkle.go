@@ -0,0 +1,67 @@
+package solar
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/golang/geo/r2"
+)
+
+// DrawTwinkle renders a dim star field, each led slowly brightening and fading on its own
+type DrawTwinkle struct {
+
+	// seconds since the twinkle started
+	time float64
+
+	// average seconds between twinkles of one led
+	period float64
+
+	// color of the brightest twinkle
+	color color.RGBA
+
+	// z position of the stars
+	zindex ZIndex
+}
+
+var _ Drawable = &DrawTwinkle{}
+
+// NewTwinkle Construct a star field
+func NewTwinkle() *DrawTwinkle {
+	return &DrawTwinkle{
+		period: 6.0,
+		color:  color.RGBA{R: 180, G: 190, B: 255, A: 90},
+		zindex: 0,
+	}
+}
+
+// Affects every planet
+func (twinkle *DrawTwinkle) Affects(position r2.Point, radius float64) bool {
+	return true
+}
+
+// ColorAt Returns the color at position blended on top of baseColor
+func (twinkle *DrawTwinkle) ColorAt(position r2.Point, baseColor RGBA) (color RGBA) {
+	// hash the position so each led gets its own phase and speed
+	seed := math.Sin(position.X*12.9898+position.Y*78.233) * 43758.5453
+	seed -= math.Floor(seed)
+
+	speed := 2 * math.Pi / (twinkle.period * (0.6 + 0.8*seed))
+	intensity := math.Pow(math.Max(0, math.Sin(twinkle.time*speed+seed*2*math.Pi)), 8)
+	if intensity <= 0 {
+		return baseColor
+	}
+
+	color = RGBA{twinkle.color.R, twinkle.color.G, twinkle.color.B, uint8(intensity * float64(twinkle.color.A))}
+	return color.BlendWith(baseColor)
+}
+
+// ZIndex of the stars
+func (twinkle *DrawTwinkle) ZIndex() ZIndex {
+	return twinkle.zindex
+}
+
+// Animate stars
+func (twinkle *DrawTwinkle) Animate(dt float64) bool {
+	twinkle.time += dt
+	return true
+}
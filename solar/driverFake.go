@@ -0,0 +1,57 @@
+package solar
+
+// FakeDriver StripDriver that keeps every rendered frame in memory instead of driving hardware
+type FakeDriver struct {
+
+	// Channels passed to Init
+	Channels []StripChannel
+
+	// Frames every rendered frame, indexed by frame, channel, then led
+	Frames [][][]uint32
+
+	// Finished if Fini has been called
+	Finished bool
+
+	// values set since the last Render
+	current [][]uint32
+}
+
+var _ StripDriver = &FakeDriver{}
+
+// Init prepare an output for each channel
+func (driver *FakeDriver) Init(channels []StripChannel) error {
+	driver.Channels = channels
+	driver.current = make([][]uint32, len(channels))
+	for channel := range channels {
+		driver.current[channel] = make([]uint32, channels[channel].LedCount)
+	}
+	return nil
+}
+
+// SetLed set the packed 24 bit color of led index on channel
+func (driver *FakeDriver) SetLed(channel int, index int, value uint32) {
+	driver.current[channel][index] = value
+}
+
+// Render record a copy of the current values as a frame
+func (driver *FakeDriver) Render() error {
+	frame := make([][]uint32, len(driver.current))
+	for channel, values := range driver.current {
+		frame[channel] = append([]uint32{}, values...)
+	}
+	driver.Frames = append(driver.Frames, frame)
+	return nil
+}
+
+// Fini mark the driver as finished
+func (driver *FakeDriver) Fini() {
+	driver.Finished = true
+}
+
+// LastFrame the most recently rendered frame, nil if nothing has been rendered
+func (driver *FakeDriver) LastFrame() [][]uint32 {
+	if len(driver.Frames) == 0 {
+		return nil
+	}
+	return driver.Frames[len(driver.Frames)-1]
+}
@@ -0,0 +1,33 @@
+// +build !linux !cgo !arm,!arm64
+
+package solar
+
+import (
+	"errors"
+)
+
+// unsupportedDriver stands in for the ws2811 driver when not built for a raspberry pi
+type unsupportedDriver struct{}
+
+var _ StripDriver = &unsupportedDriver{}
+
+// newStripDriver return the driver for the hardware this was built for
+func newStripDriver() StripDriver {
+	return &unsupportedDriver{}
+}
+
+// Init always fails, there are no strips to drive
+func (driver *unsupportedDriver) Init(channels []StripChannel) error {
+	return errors.New("led strips are only supported when built with cgo for a raspberry pi")
+}
+
+// SetLed does nothing
+func (driver *unsupportedDriver) SetLed(channel int, index int, value uint32) {}
+
+// Render does nothing
+func (driver *unsupportedDriver) Render() error {
+	return nil
+}
+
+// Fini does nothing
+func (driver *unsupportedDriver) Fini() {}
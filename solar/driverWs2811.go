@@ -0,0 +1,112 @@
+// +build linux,cgo
+// +build arm arm64
+
+package solar
+
+/*
+#cgo LDFLAGS: -lws2811 -lm
+#include <stdint.h>
+#include <string.h>
+#include <ws2811.h>
+
+// the library keeps pointers into this struct between calls, so it lives in C memory
+static ws2811_t strip;
+
+static int strip_init(int channel_count, int *pins, int *counts, int *brightness) {
+	memset(&strip, 0, sizeof(strip));
+	strip.freq = WS2811_TARGET_FREQ;
+	strip.dmanum = 10;
+
+	for (int i = 0; i < channel_count && i < RPI_PWM_CHANNELS; i++) {
+		strip.channel[i].gpionum = pins[i];
+		strip.channel[i].count = counts[i];
+		strip.channel[i].brightness = brightness[i];
+		// colors are already packed in the order the strip expects
+		strip.channel[i].strip_type = WS2811_STRIP_RGB;
+	}
+
+	return ws2811_init(&strip);
+}
+
+static void strip_set_led(int channel, int index, uint32_t value) {
+	strip.channel[channel].leds[index] = value;
+}
+
+static int strip_render() {
+	return ws2811_render(&strip);
+}
+
+static void strip_fini() {
+	for (int i = 0; i < RPI_PWM_CHANNELS; i++) {
+		if (strip.channel[i].leds != NULL) {
+			memset(strip.channel[i].leds, 0, sizeof(ws2811_led_t) * strip.channel[i].count);
+		}
+	}
+	ws2811_render(&strip);
+	ws2811_fini(&strip);
+}
+*/
+import "C"
+
+import (
+	"fmt"
+)
+
+// ws2811Driver drives up to two strips at once from the pwm channels of a raspberry pi using the rpi_ws281x library
+type ws2811Driver struct {
+	// led count of each channel, to keep SetLed inside the buffers owned by the library
+	ledCount []int
+}
+
+var _ StripDriver = &ws2811Driver{}
+
+// newStripDriver return the driver for the hardware this was built for
+func newStripDriver() StripDriver {
+	return &ws2811Driver{}
+}
+
+// Init prepare an output for each channel
+func (driver *ws2811Driver) Init(channels []StripChannel) error {
+	if len(channels) > C.RPI_PWM_CHANNELS {
+		return fmt.Errorf("ws2811 supports %d channels, %d were configured", C.RPI_PWM_CHANNELS, len(channels))
+	}
+
+	pins := make([]C.int, C.RPI_PWM_CHANNELS)
+	counts := make([]C.int, C.RPI_PWM_CHANNELS)
+	brightness := make([]C.int, C.RPI_PWM_CHANNELS)
+	driver.ledCount = make([]int, len(channels))
+	for i, channel := range channels {
+		pins[i] = C.int(channel.Pin)
+		counts[i] = C.int(channel.LedCount)
+		brightness[i] = C.int(channel.Brightness)
+		driver.ledCount[i] = channel.LedCount
+	}
+
+	result := C.strip_init(C.int(len(channels)), &pins[0], &counts[0], &brightness[0])
+	if result != 0 {
+		return fmt.Errorf("ws2811 init failed: %s", C.GoString(C.ws2811_get_return_t_str(C.ws2811_return_t(result))))
+	}
+	return nil
+}
+
+// SetLed set the packed 24 bit color of led index on channel
+func (driver *ws2811Driver) SetLed(channel int, index int, value uint32) {
+	if channel >= len(driver.ledCount) || index >= driver.ledCount[channel] {
+		return
+	}
+	C.strip_set_led(C.int(channel), C.int(index), C.uint32_t(value))
+}
+
+// Render send the colors of every channel to the strips, both channels are sent at the same time
+func (driver *ws2811Driver) Render() error {
+	result := C.strip_render()
+	if result != 0 {
+		return fmt.Errorf("ws2811 render failed: %s", C.GoString(C.ws2811_get_return_t_str(C.ws2811_return_t(result))))
+	}
+	return nil
+}
+
+// Fini turn off every led and release the outputs
+func (driver *ws2811Driver) Fini() {
+	C.strip_fini()
+}
@@ -0,0 +1,115 @@
+package solar
+
+import (
+	"math"
+)
+
+// Easing maps linear progress from 0 to 1 onto eased progress, which may overshoot for springy curves
+type Easing func(t float64) float64
+
+// Easings every easing by name, for use in configuration
+var Easings = map[string]Easing{
+	"linear":       Linear,
+	"easeIn":       EaseIn,
+	"easeOut":      EaseOut,
+	"easeInOut":    EaseInOut,
+	"cubicIn":      CubicIn,
+	"cubicOut":     CubicOut,
+	"cubicInOut":   CubicInOut,
+	"bounce":       Bounce,
+	"spring":       Spring,
+	"step":         Step,
+	"sineInOut":    SineInOut,
+	"quarticInOut": QuarticInOut,
+}
+
+// Linear constant speed
+func Linear(t float64) float64 {
+	return t
+}
+
+// Step stays at the start value until the very end, for instant changes
+func Step(t float64) float64 {
+	if t < 1 {
+		return 0
+	}
+	return 1
+}
+
+// EaseIn starts slow, quadratic
+func EaseIn(t float64) float64 {
+	return t * t
+}
+
+// EaseOut ends slow, quadratic
+func EaseOut(t float64) float64 {
+	return t * (2 - t)
+}
+
+// EaseInOut starts and ends slow, quadratic
+func EaseInOut(t float64) float64 {
+	if t < 0.5 {
+		return 2 * t * t
+	}
+	return -1 + (4-2*t)*t
+}
+
+// CubicIn starts slow
+func CubicIn(t float64) float64 {
+	return t * t * t
+}
+
+// CubicOut ends slow
+func CubicOut(t float64) float64 {
+	t--
+	return t*t*t + 1
+}
+
+// CubicInOut starts and ends slow
+func CubicInOut(t float64) float64 {
+	if t < 0.5 {
+		return 4 * t * t * t
+	}
+	t = 2*t - 2
+	return t*t*t/2 + 1
+}
+
+// QuarticInOut starts and ends slower than cubic
+func QuarticInOut(t float64) float64 {
+	if t < 0.5 {
+		return 8 * t * t * t * t
+	}
+	t--
+	return 1 - 8*t*t*t*t
+}
+
+// SineInOut gentle start and end following a cosine
+func SineInOut(t float64) float64 {
+	return (1 - math.Cos(math.Pi*t)) / 2
+}
+
+// Bounce hits the end value and bounces back a few times before settling
+func Bounce(t float64) float64 {
+	const n, d = 7.5625, 2.75
+	switch {
+	case t < 1/d:
+		return n * t * t
+	case t < 2/d:
+		t -= 1.5 / d
+		return n*t*t + 0.75
+	case t < 2.5/d:
+		t -= 2.25 / d
+		return n*t*t + 0.9375
+	default:
+		t -= 2.625 / d
+		return n*t*t + 0.984375
+	}
+}
+
+// Spring overshoots the end value and oscillates into it like a damped spring
+func Spring(t float64) float64 {
+	if t >= 1 {
+		return 1
+	}
+	return 1 - math.Exp(-6*t)*math.Cos(3*math.Pi*t)
+}
@@ -0,0 +1,84 @@
+package solar
+
+import (
+	"math"
+	"time"
+)
+
+// orbitalElements mean Keplerian elements at J2000 and their change per century, angles in degrees and distances in AU
+type orbitalElements struct {
+	semiMajorAxis, eccentricity, inclination, meanLongitude, perihelionLongitude, nodeLongitude       float64
+	semiMajorAxisRate, eccentricityRate, inclinationRate, meanLongitudeRate, perihelionRate, nodeRate float64
+}
+
+// planetElements from Standish, "Keplerian Elements for Approximate Positions of the Major Planets", good from 1800 to 2050
+// Earth is the Earth-Moon barycenter and the Sun has no entry
+var planetElements = map[PlanetIndex]orbitalElements{
+	Mercury: {0.38709927, 0.20563593, 7.00497902, 252.25032350, 77.45779628, 48.33076593,
+		0.00000037, 0.00001906, -0.00594749, 149472.67411175, 0.16047689, -0.12534081},
+	Venus: {0.72333566, 0.00677672, 3.39467605, 181.97909950, 131.60246718, 76.67984255,
+		0.00000390, -0.00004107, -0.00078890, 58517.81538729, 0.00268329, -0.27769418},
+	Earth: {1.00000261, 0.01671123, -0.00001531, 100.46457166, 102.93768193, 0.0,
+		0.00000562, -0.00004392, -0.01294668, 35999.37244981, 0.32327364, 0.0},
+	Mars: {1.52371034, 0.09339410, 1.84969142, -4.55343205, -23.94362959, 49.55953891,
+		0.00001847, 0.00007882, -0.00813131, 19140.30268499, 0.44441088, -0.29257343},
+	Jupiter: {5.20288700, 0.04838624, 1.30439695, 34.39644051, 14.72847983, 100.47390909,
+		-0.00011607, -0.00013253, -0.00183714, 3034.74612775, 0.21252668, 0.20469106},
+	Saturn: {9.53667594, 0.05386179, 2.48599187, 49.95424423, 92.59887831, 113.66242448,
+		-0.00125060, -0.00050991, 0.00193609, 1222.49362201, -0.41897216, -0.28867794},
+	Uranus: {19.18916464, 0.04725744, 0.77263783, 313.23810451, 170.95427630, 74.01692503,
+		-0.00196176, -0.00004397, -0.00242939, 428.48202785, 0.40805281, 0.04240589},
+	Neptune: {30.06992276, 0.00859048, 1.77004347, -55.12002969, 44.96476227, 131.78422574,
+		0.00026291, 0.00005105, 0.00035372, 218.45945325, -0.32241464, -0.00508664},
+}
+
+// HeliocentricPosition ecliptic coordinates of planet in AU at t, the Sun is at the origin
+func HeliocentricPosition(planet PlanetIndex, t time.Time) (x float64, y float64, z float64) {
+	elements, ok := planetElements[planet]
+	if !ok {
+		return 0, 0, 0
+	}
+
+	centuries := (julianDate(t) - 2451545.0) / 36525
+	radians := func(degrees float64) float64 { return degrees * math.Pi / 180 }
+
+	a := elements.semiMajorAxis + elements.semiMajorAxisRate*centuries
+	e := elements.eccentricity + elements.eccentricityRate*centuries
+	inclination := radians(elements.inclination + elements.inclinationRate*centuries)
+	meanLongitude := elements.meanLongitude + elements.meanLongitudeRate*centuries
+	perihelion := elements.perihelionLongitude + elements.perihelionRate*centuries
+	node := radians(elements.nodeLongitude + elements.nodeRate*centuries)
+	argument := radians(perihelion) - node
+
+	// solve Kepler's equation for the eccentric anomaly
+	meanAnomaly := radians(math.Mod(meanLongitude-perihelion, 360))
+	eccentricAnomaly := meanAnomaly + e*math.Sin(meanAnomaly)
+	for i := 0; i < 10; i++ {
+		eccentricAnomaly -= (eccentricAnomaly - e*math.Sin(eccentricAnomaly) - meanAnomaly) / (1 - e*math.Cos(eccentricAnomaly))
+	}
+
+	// position in the plane of the orbit, then rotated into the ecliptic
+	orbitX := a * (math.Cos(eccentricAnomaly) - e)
+	orbitY := a * math.Sqrt(1-e*e) * math.Sin(eccentricAnomaly)
+
+	cosArgument, sinArgument := math.Cos(argument), math.Sin(argument)
+	cosNode, sinNode := math.Cos(node), math.Sin(node)
+	cosInclination, sinInclination := math.Cos(inclination), math.Sin(inclination)
+
+	x = (cosArgument*cosNode-sinArgument*sinNode*cosInclination)*orbitX + (-sinArgument*cosNode-cosArgument*sinNode*cosInclination)*orbitY
+	y = (cosArgument*sinNode+sinArgument*cosNode*cosInclination)*orbitX + (-sinArgument*sinNode+cosArgument*cosNode*cosInclination)*orbitY
+	z = sinArgument*sinInclination*orbitX + cosArgument*sinInclination*orbitY
+	return x, y, z
+}
+
+// GeocentricLongitude ecliptic longitude of planet as seen from Earth at t, in degrees 0 to 360 measured from the equinox of date
+func GeocentricLongitude(planet PlanetIndex, t time.Time) float64 {
+	earthX, earthY, _ := HeliocentricPosition(Earth, t)
+	x, y, _ := HeliocentricPosition(planet, t)
+
+	// the elements are for the J2000 equinox, which precesses about 1.4 degrees a century
+	precession := 1.3969713 * (julianDate(t) - 2451545.0) / 36525
+
+	longitude := math.Atan2(y-earthY, x-earthX)*180/math.Pi + precession
+	return math.Mod(math.Mod(longitude, 360)+360, 360)
+}
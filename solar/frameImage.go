@@ -0,0 +1,43 @@
+package solar
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// drawFrame plots every led of solarSystem into frame as a dot dotSize pixels across, colors are in strip order
+// scale is the number of pixels per unit of wall position
+func drawFrame(solarSystem *System, colors []RGBA, frame *image.RGBA, scale float64, dotSize float64) {
+	radius := dotSize / 2
+
+	ledIndex := 0
+	for planetIndex := 0; planetIndex < PlanetCount; planetIndex++ {
+		for led := 0; led < solarSystem.planets[planetIndex].ledCount; led++ {
+			ledPosition := solarSystem.LedPosition(PlanetIndex(planetIndex), led)
+
+			curColor := colors[ledIndex]
+			curColor.A = 255 // for rendering to image dont want to blend to nothing
+			ledIndex++
+
+			centerX := ledPosition.X * scale
+			centerY := ledPosition.Y * scale
+
+			if dotSize <= 1 {
+				frame.SetRGBA(int(centerX), int(centerY), color.RGBA(curColor))
+				continue
+			}
+
+			// fill every pixel whose center is inside the dot
+			for y := int(math.Floor(centerY - radius)); y <= int(math.Ceil(centerY+radius)); y++ {
+				for x := int(math.Floor(centerX - radius)); x <= int(math.Ceil(centerX+radius)); x++ {
+					dx := float64(x) + 0.5 - centerX
+					dy := float64(y) + 0.5 - centerY
+					if dx*dx+dy*dy <= radius*radius {
+						frame.SetRGBA(x, y, color.RGBA(curColor))
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package solar
+
+import (
+	"sort"
+)
+
+// GradientStop color at a position in a Gradient
+type GradientStop struct {
+
+	// where the color is, 0 to 1
+	Position float64
+
+	Color RGBA
+}
+
+// Gradient colors blended smoothly between stops, sorted by position
+type Gradient []GradientStop
+
+// NewGradient a gradient through colors spaced evenly from 0 to 1
+func NewGradient(colors ...RGBA) Gradient {
+	gradient := make(Gradient, len(colors))
+	for i, color := range colors {
+		gradient[i] = GradientStop{Color: color}
+		if len(colors) > 1 {
+			gradient[i].Position = float64(i) / float64(len(colors)-1)
+		}
+	}
+	return gradient
+}
+
+// At color at position blended in RGB, clamped to the first and last stops
+func (gradient Gradient) At(position float64) RGBA {
+	return gradient.blend(position, BlendRGB)
+}
+
+// blend color at position with stops blended in space, clamped to the first and last stops
+func (gradient Gradient) blend(position float64, space ColorSpace) RGBA {
+	if len(gradient) == 0 {
+		return RGBA{}
+	}
+
+	next := sort.Search(len(gradient), func(i int) bool { return gradient[i].Position >= position })
+	if next == 0 {
+		return gradient[0].Color
+	}
+	if next == len(gradient) {
+		return gradient[len(gradient)-1].Color
+	}
+
+	from, to := gradient[next-1], gradient[next]
+	progress := 0.0
+	if to.Position > from.Position {
+		progress = (position - from.Position) / (to.Position - from.Position)
+	}
+	return blendColors(from.Color, to.Color, progress, space)
+}
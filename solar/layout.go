@@ -0,0 +1,194 @@
+package solar
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/golang/geo/r2"
+)
+
+// Layout the physical details of an install, stored as json so a wall can be calibrated without changing code
+type Layout struct {
+	Planets []PlanetLayout `json:"planets"`
+
+	// order the planets are chained in on each output channel, defaults to Sun to Neptune on channel 0
+	Strip []StripSegment `json:"strip,omitempty"`
+
+	// how each output channel is driven, indexed by channel
+	Channels []ChannelLayout `json:"channels,omitempty"`
+
+	// where the wall is, used for sunrise and sunset
+	Location *Location `json:"location,omitempty"`
+
+	// brightness changes through the day
+	BrightnessSchedule []BrightnessRule `json:"brightnessSchedule,omitempty"`
+
+	// scene changes through the day
+	SceneSchedule []SceneRule `json:"sceneSchedule,omitempty"`
+
+	// how lively the Sun effect is
+	Sun *SunLayout `json:"sun,omitempty"`
+
+	// how the clock scene tells time, in the time zone of the location
+	Clock *ClockLayout `json:"clock,omitempty"`
+}
+
+// ChannelLayout the gpio pin and type of strip on one output channel
+type ChannelLayout struct {
+	Pin int `json:"pin"`
+
+	// 0 to 255
+	Brightness int `json:"brightness"`
+
+	// order the strip expects colors in, such as grb for ws2812
+	StripType string `json:"stripType"`
+}
+
+// PlanetLayout where a planet is on the wall and how its leds were installed
+type PlanetLayout struct {
+	Name           string  `json:"name"`
+	X              float64 `json:"x"`
+	Y              float64 `json:"y"`
+	Radius         float64 `json:"radius"`
+	LedCount       int     `json:"ledCount"`
+	AngleOffset    float64 `json:"angleOffset"`
+	AngleDirection float64 `json:"angleDirection"`
+}
+
+// LoadLayout replace the planets of solarSystem with the ones in the layout file at path
+// Planets missing from the file keep their current values
+func LoadLayout(path string, solarSystem *System) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var layout Layout
+	if err := json.Unmarshal(data, &layout); err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+
+	for _, planetLayout := range layout.Planets {
+		planetIndex, ok := ParsePlanetIndex(planetLayout.Name)
+		if !ok {
+			return fmt.Errorf("%s: unknown planet %q", path, planetLayout.Name)
+		}
+		if planetLayout.AngleDirection != 1 && planetLayout.AngleDirection != -1 {
+			return fmt.Errorf("%s: angleDirection of %v must be 1 or -1", path, planetIndex)
+		}
+		if planetLayout.LedCount < 0 {
+			return fmt.Errorf("%s: ledCount of %v must not be negative", path, planetIndex)
+		}
+		if planetLayout.Radius <= 0 {
+			return fmt.Errorf("%s: radius of %v must be positive", path, planetIndex)
+		}
+
+		solarSystem.planets[planetIndex] = Planet{
+			position:       r2.Point{X: planetLayout.X, Y: planetLayout.Y},
+			radius:         planetLayout.Radius,
+			ledCount:       planetLayout.LedCount,
+			angleOffset:    planetLayout.AngleOffset,
+			angleDirection: planetLayout.AngleDirection,
+		}
+	}
+
+	stripMap, err := NewStripMap(solarSystem, layout.Strip)
+	if err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	solarSystem.stripMap = stripMap
+
+	if len(layout.Channels) > 0 {
+		for channel, channelLayout := range layout.Channels {
+			if _, ok := stripTypeShifts[channelLayout.StripType]; !ok {
+				return fmt.Errorf("%s: channel %d has unknown stripType %q", path, channel, channelLayout.StripType)
+			}
+			if channelLayout.Brightness < 0 || channelLayout.Brightness > 255 {
+				return fmt.Errorf("%s: channel %d brightness must be 0 to 255", path, channel)
+			}
+		}
+		solarSystem.channels = layout.Channels
+	}
+	if len(solarSystem.channels) < stripMap.ChannelCount() {
+		return fmt.Errorf("%s: strip uses %d channels but only %d are configured", path, stripMap.ChannelCount(), len(solarSystem.channels))
+	}
+
+	if layout.Location != nil && layout.Location.TimeZone != "" {
+		if _, err := time.LoadLocation(layout.Location.TimeZone); err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+	}
+	solarSystem.location = layout.Location
+
+	if err := solarSystem.brightness.SetSchedule(layout.BrightnessSchedule, layout.Location); err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+
+	if err := solarSystem.scenes.SetRules(layout.SceneSchedule, layout.Location); err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+
+	if layout.Sun != nil {
+		if layout.Sun.Intensity < 0 {
+			return fmt.Errorf("%s: sun intensity must not be negative", path)
+		}
+		if layout.Sun.ActivityFile != "" {
+			activity, err := LoadSolarActivity(layout.Sun.ActivityFile)
+			if err != nil {
+				return fmt.Errorf("%s: %v", path, err)
+			}
+			solarSystem.solarActivity = activity
+		}
+		solarSystem.sun = *layout.Sun
+	}
+
+	if layout.Clock != nil {
+		if layout.Clock.Hours != 12 && layout.Clock.Hours != 24 {
+			return fmt.Errorf("%s: clock hours must be 12 or 24", path)
+		}
+		solarSystem.clockFace = *layout.Clock
+	}
+
+	// the drawables of the scene showing were built for the old planets
+	if solarSystem.scenes.held {
+		return nil
+	}
+	return solarSystem.SetScene(solarSystem.SceneName())
+}
+
+// SaveLayout write the planets of solarSystem to the layout file at path
+func SaveLayout(path string, solarSystem *System) error {
+	layout := Layout{
+		Strip:              solarSystem.stripMap.segments,
+		Channels:           solarSystem.channels,
+		Location:           solarSystem.location,
+		BrightnessSchedule: solarSystem.brightness.schedule,
+		SceneSchedule:      solarSystem.scenes.rules,
+	}
+	if solarSystem.sun != DefaultSunLayout {
+		layout.Sun = &solarSystem.sun
+	}
+	if solarSystem.clockFace != DefaultClockLayout {
+		layout.Clock = &solarSystem.clockFace
+	}
+	for planetIndex, planet := range solarSystem.planets {
+		layout.Planets = append(layout.Planets, PlanetLayout{
+			Name:           PlanetIndex(planetIndex).String(),
+			X:              planet.position.X,
+			Y:              planet.position.Y,
+			Radius:         planet.radius,
+			LedCount:       planet.ledCount,
+			AngleOffset:    planet.angleOffset,
+			AngleDirection: planet.angleDirection,
+		})
+	}
+
+	data, err := json.MarshalIndent(layout, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, append(data, '\n'), 0644)
+}
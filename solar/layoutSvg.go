@@ -0,0 +1,94 @@
+package solar
+
+import (
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/golang/geo/r2"
+)
+
+// pixels per unit of wall position in the width and height of the svg, the viewBox stays in wall units
+const svgScale = 10
+
+// WriteLayoutSVG draws the physical layout: each planet with its leds numbered in strip order from led 0
+// If colored is true each led is filled with its color in the current frame
+func WriteLayoutSVG(w io.Writer, solarSystem *System, colored bool) error {
+	var colors []RGBA
+	if colored {
+		colors = make([]RGBA, solarSystem.LedCount())
+		solarSystem.RenderColors(colors)
+	}
+
+	_, err := fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif">
+<defs><marker id="arrow" viewBox="0 0 10 10" refX="5" refY="5" markerWidth="4" markerHeight="4" orient="auto"><path d="M 0 0 L 10 5 L 0 10 z" fill="#0f0"/></marker></defs>
+<rect width="%d" height="%d" fill="#222"/>
+`, WallWidth*svgScale, WallHeight*svgScale, WallWidth, WallHeight, WallWidth, WallHeight)
+	if err != nil {
+		return err
+	}
+
+	ledIndex := 0
+	for planetIndex := 0; planetIndex < PlanetCount; planetIndex++ {
+		planet := solarSystem.planets[planetIndex]
+		ringRadius := planet.radius * 0.5
+
+		fmt.Fprintf(w, `<g id="%v">
+<circle cx="%.3f" cy="%.3f" r="%.3f" fill="none" stroke="#666" stroke-width="0.1"/>
+<text x="%.3f" y="%.3f" font-size="1.5" fill="#aaa" text-anchor="middle">%v</text>
+`, PlanetIndex(planetIndex), planet.position.X, planet.position.Y, ringRadius,
+			planet.position.X, planet.position.Y+ringRadius+3, PlanetIndex(planetIndex))
+
+		for led := 0; led < planet.ledCount; led++ {
+			ledPosition := solarSystem.LedPosition(PlanetIndex(planetIndex), led)
+
+			fill := "none"
+			if colored {
+				color := colors[ledIndex]
+				red, green, blue := (int(color.R)*int(color.A))>>8, (int(color.G)*int(color.A))>>8, (int(color.B)*int(color.A))>>8
+				fill = fmt.Sprintf("rgb(%d,%d,%d)", red, green, blue)
+			}
+
+			channel, stripIndex := solarSystem.stripMap.Physical(ledIndex)
+
+			// led 0 is outlined in green so the start of each strip stands out
+			stroke := "#ccc"
+			if led == 0 {
+				stroke = "#0f0"
+			}
+
+			// number sits just outside the ring, in line with the led
+			outward := ledPosition.Sub(planet.position).Normalize()
+			label := planet.position.Add(outward.Mul(ringRadius + 0.9))
+
+			fmt.Fprintf(w, `<circle cx="%.3f" cy="%.3f" r="0.3" fill="%s" stroke="%s" stroke-width="0.08"><title>%v led %d, channel %d strip index %d</title></circle>
+<text x="%.3f" y="%.3f" font-size="0.55" fill="#ccc" text-anchor="middle" dominant-baseline="central">%d</text>
+`, ledPosition.X, ledPosition.Y, fill, stroke, PlanetIndex(planetIndex), led, channel, stripIndex, label.X, label.Y, led)
+
+			ledIndex++
+		}
+
+		// arrow from led 0 toward led 1 shows which way the strip runs
+		if planet.ledCount > 1 {
+			start := solarSystem.LedPosition(PlanetIndex(planetIndex), 0)
+			angle := math.Atan2(start.Y-planet.position.Y, start.X-planet.position.X) + planet.angleDirection*0.6
+			end := planet.position.Add(r2.Point{X: math.Cos(angle), Y: math.Sin(angle)}.Mul(ringRadius * 0.6))
+			fmt.Fprintf(w, `<path d="M %.3f %.3f A %.3f %.3f 0 0 %d %.3f %.3f" fill="none" stroke="#0f0" stroke-width="0.1" marker-end="url(#arrow)"/>
+`, planet.position.X+(start.X-planet.position.X)*0.6, planet.position.Y+(start.Y-planet.position.Y)*0.6,
+				ringRadius*0.6, ringRadius*0.6, sweepFlag(planet.angleDirection), end.X, end.Y)
+		}
+
+		fmt.Fprint(w, "</g>\n")
+	}
+
+	_, err = fmt.Fprint(w, "</svg>\n")
+	return err
+}
+
+// sweepFlag svg arc flag for the direction leds are numbered in, positive angles are clockwise on the wall
+func sweepFlag(angleDirection float64) int {
+	if angleDirection < 0 {
+		return 0
+	}
+	return 1
+}
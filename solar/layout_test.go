@@ -0,0 +1,79 @@
+package solar
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang/geo/r2"
+)
+
+func TestLoadLayoutRejectsBadPlanets(t *testing.T) {
+	dir, err := ioutil.TempDir("", "layout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := map[string]string{
+		"direction": `{"name":"Earth","x":40,"y":10,"radius":4,"ledCount":17,"angleDirection":0}`,
+		"ledCount":  `{"name":"Earth","x":40,"y":10,"radius":4,"ledCount":-1,"angleDirection":1}`,
+		"radius":    `{"name":"Earth","x":40,"y":10,"radius":0,"ledCount":17,"angleDirection":1}`,
+	}
+	for field, planet := range tests {
+		path := filepath.Join(dir, field+".json")
+		if err := ioutil.WriteFile(path, []byte(`{"planets":[`+planet+`]}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		err := LoadLayout(path, DefaultSystem())
+		if err == nil {
+			t.Errorf("%s: loaded", field)
+			continue
+		}
+		if !strings.HasPrefix(err.Error(), path+": ") || !strings.Contains(err.Error(), field) {
+			t.Errorf("%s: error %q should name the file and the field", field, err)
+		}
+	}
+}
+
+func TestLoadLayoutRebuildsScene(t *testing.T) {
+	dir, err := ioutil.TempDir("", "layout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "layout.json")
+	planet := `{"name":"Mars","x":20,"y":70,"radius":4,"ledCount":17,"angleDirection":1}`
+	if err := ioutil.WriteFile(path, []byte(`{"planets":[`+planet+`]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	solarSystem := DefaultSystem()
+	if err := LoadLayout(path, solarSystem); err != nil {
+		t.Fatal(err)
+	}
+	if solarSystem.SceneName() != "orrery" {
+		t.Fatalf("scene changed to %q", solarSystem.SceneName())
+	}
+
+	// the orrery puts a rotating line on every planet, one of them must start where Mars moved to
+	moved := r2.Point{X: 20, Y: 70}
+	found := false
+	for element := solarSystem.drawables.Front(); element != nil; element = element.Next() {
+		if line, ok := element.Value.(*DrawRotatingLine); ok {
+			if line.startPosition == (r2.Point{X: 60, Y: 19}) {
+				t.Error("a rotating line still starts where Mars was")
+			}
+			if line.startPosition == moved {
+				found = true
+			}
+		}
+	}
+	if !found {
+		t.Errorf("no rotating line starts at %v", moved)
+	}
+}
@@ -0,0 +1,252 @@
+package solar
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"golang.org/x/net/ipv4"
+)
+
+const (
+	sacnPort   = 5568
+	artNetPort = 6454
+
+	// offset of the first dmx slot in each packet type
+	sacnDataOffset   = 126
+	artNetDataOffset = 18
+)
+
+var (
+	sacnPacketIdentifier = []byte("ASC-E1.17\x00\x00\x00")
+	artNetIdentifier     = []byte("Art-Net\x00")
+)
+
+// LightingInput receives dmx from a lighting console over sACN (E1.31) or Art-Net so it can drive the wall
+// Each planet is its own universe, starting at firstUniverse for the Sun, with led 0 on channels 1-3 as RGB
+type LightingInput struct {
+
+	// sacn or artnet
+	protocol string
+
+	// universe mapped to the Sun, the other planets follow in PlanetIndex order
+	firstUniverse int
+
+	// how long without packets before the wall goes back to its own animation
+	timeout time.Duration
+
+	// socket being listened on
+	conn *net.UDPConn
+
+	// index of the first led of each planet in strip order
+	planetOffset [PlanetCount]int
+
+	// guards colors and lastPacket which are written by the network goroutine
+	lock sync.Mutex
+
+	// most recently received color of each led in strip order
+	colors []RGBA
+
+	// when dmx data was last received for each planet's universe, zero if the source said it stopped
+	lastPacket [PlanetCount]time.Time
+
+	// colors handed to the system, reused every frame
+	override []RGBA
+
+	// if the previous call to Apply handed the wall to the input
+	active bool
+}
+
+// NewLightingInput start listening for protocol "sacn" or "artnet"
+func NewLightingInput(solarSystem *System, protocol string, firstUniverse int, timeout time.Duration) (*LightingInput, error) {
+	input := &LightingInput{
+		protocol:      protocol,
+		firstUniverse: firstUniverse,
+		timeout:       timeout,
+		colors:        make([]RGBA, solarSystem.LedCount()),
+		override:      make([]RGBA, solarSystem.LedCount()),
+	}
+
+	offset := 0
+	for planetIndex := 0; planetIndex < PlanetCount; planetIndex++ {
+		input.planetOffset[planetIndex] = offset
+		offset += solarSystem.planets[planetIndex].ledCount
+	}
+
+	var port int
+	switch protocol {
+	case "sacn":
+		port = sacnPort
+	case "artnet":
+		port = artNetPort
+	default:
+		return nil, fmt.Errorf("unknown lighting input protocol %q", protocol)
+	}
+
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{Port: port})
+	if err != nil {
+		return nil, err
+	}
+	input.conn = conn
+
+	if protocol == "sacn" {
+		// sACN is sent to a multicast group per universe, 239.255.<universe high>.<universe low>, all joined on the one socket
+		packetConn := ipv4.NewPacketConn(conn)
+		for planetIndex := 0; planetIndex < PlanetCount; planetIndex++ {
+			universe := firstUniverse + planetIndex
+			group := &net.UDPAddr{IP: net.IPv4(239, 255, byte(universe>>8), byte(universe))}
+			if err := packetConn.JoinGroup(nil, group); err != nil {
+				conn.Close()
+				return nil, err
+			}
+		}
+	}
+
+	go input.receive(conn)
+
+	log.Print("Listening for ", protocol, " on universes ", firstUniverse, "-", firstUniverse+PlanetCount-1)
+	return input, nil
+}
+
+// Close stop listening
+func (input *LightingInput) Close() {
+	input.conn.Close()
+}
+
+// Apply hands the received colors to solarSystem, or returns it to its own animation once the input has been silent for the timeout
+func (input *LightingInput) Apply(solarSystem *System) {
+	input.lock.Lock()
+	defer input.lock.Unlock()
+
+	active := false
+	for _, lastPacket := range input.lastPacket {
+		if time.Since(lastPacket) < input.timeout {
+			active = true
+		}
+	}
+	if active != input.active {
+		if active {
+			log.Print("Lighting input took over the wall")
+		} else {
+			log.Print("Lighting input went silent, resuming animation")
+		}
+		input.active = active
+	}
+
+	if !active {
+		solarSystem.SetLedOverride(nil)
+		return
+	}
+
+	copy(input.override, input.colors)
+	solarSystem.SetLedOverride(input.override)
+}
+
+// receive read packets from conn until it is closed
+func (input *LightingInput) receive(conn *net.UDPConn) {
+	buffer := make([]byte, 1024)
+	for {
+		length, _, err := conn.ReadFromUDP(buffer)
+		if err != nil {
+			return
+		}
+
+		var universe int
+		var data []byte
+		var ok bool
+		if input.protocol == "sacn" {
+			universe, data, ok = parseSacn(buffer[:length])
+		} else {
+			universe, data, ok = parseArtNet(buffer[:length])
+		}
+		if !ok {
+			continue
+		}
+
+		input.setUniverse(universe, data)
+	}
+}
+
+// setUniverse copy dmx data into the colors of the planet mapped to universe, nil data means the source stopped sending
+func (input *LightingInput) setUniverse(universe int, data []byte) {
+	planetIndex := universe - input.firstUniverse
+	if planetIndex < 0 || planetIndex >= PlanetCount {
+		return
+	}
+
+	input.lock.Lock()
+	defer input.lock.Unlock()
+
+	if data == nil {
+		input.lastPacket[planetIndex] = time.Time{}
+		return
+	}
+	input.lastPacket[planetIndex] = time.Now()
+
+	ledCount := len(input.colors) - input.planetOffset[planetIndex]
+	if planetIndex+1 < PlanetCount {
+		ledCount = input.planetOffset[planetIndex+1] - input.planetOffset[planetIndex]
+	}
+
+	for led := 0; led < ledCount && 3*led+2 < len(data); led++ {
+		input.colors[input.planetOffset[planetIndex]+led] = RGBA{R: data[3*led], G: data[3*led+1], B: data[3*led+2], A: 255}
+	}
+}
+
+// parseSacn returns the universe and dmx data of an E1.31 data packet, data is nil for a stream terminated packet
+func parseSacn(packet []byte) (universe int, data []byte, ok bool) {
+	if len(packet) < sacnDataOffset || !bytes.Equal(packet[4:16], sacnPacketIdentifier) {
+		return 0, nil, false
+	}
+
+	// root vector 4 is E1.31 data, framing vector 2 is a dmp data packet
+	if binary.BigEndian.Uint32(packet[18:22]) != 4 || binary.BigEndian.Uint32(packet[40:44]) != 2 {
+		return 0, nil, false
+	}
+
+	universe = int(binary.BigEndian.Uint16(packet[113:115]))
+
+	const streamTerminated = 0x40
+	if packet[112]&streamTerminated != 0 {
+		return universe, nil, true
+	}
+
+	// only the null start code carries levels
+	if packet[125] != 0 {
+		return 0, nil, false
+	}
+
+	// property value count includes the start code
+	count := int(binary.BigEndian.Uint16(packet[123:125])) - 1
+	if count < 0 || sacnDataOffset+count > len(packet) {
+		return 0, nil, false
+	}
+
+	return universe, packet[sacnDataOffset : sacnDataOffset+count], true
+}
+
+// parseArtNet returns the universe and dmx data of an ArtDmx packet
+func parseArtNet(packet []byte) (universe int, data []byte, ok bool) {
+	if len(packet) < artNetDataOffset || !bytes.Equal(packet[0:8], artNetIdentifier) {
+		return 0, nil, false
+	}
+
+	const opDmx = 0x5000
+	if binary.LittleEndian.Uint16(packet[8:10]) != opDmx {
+		return 0, nil, false
+	}
+
+	// 15 bit port address made of net, sub net and universe
+	universe = int(packet[15]&0x7f)<<8 | int(packet[14])
+
+	count := int(binary.BigEndian.Uint16(packet[16:18]))
+	if artNetDataOffset+count > len(packet) {
+		return 0, nil, false
+	}
+
+	return universe, packet[artNetDataOffset : artNetDataOffset+count], true
+}
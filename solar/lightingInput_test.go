@@ -0,0 +1,55 @@
+package solar
+
+import (
+	"testing"
+	"time"
+)
+
+// newTestLightingInput a LightingInput for solarSystem that is not listening, packets are fed to setUniverse
+func newTestLightingInput(solarSystem *System) *LightingInput {
+	input := &LightingInput{
+		protocol:      "sacn",
+		firstUniverse: 1,
+		timeout:       time.Minute,
+		colors:        make([]RGBA, solarSystem.LedCount()),
+		override:      make([]RGBA, solarSystem.LedCount()),
+	}
+	offset := 0
+	for planetIndex := 0; planetIndex < PlanetCount; planetIndex++ {
+		input.planetOffset[planetIndex] = offset
+		offset += solarSystem.planets[planetIndex].ledCount
+	}
+	return input
+}
+
+func TestLightingInputTerminatesPerUniverse(t *testing.T) {
+	solarSystem := DefaultSystem()
+	input := newTestLightingInput(solarSystem)
+
+	input.setUniverse(1, []byte{255, 0, 0})
+	input.setUniverse(4, []byte{0, 0, 255})
+	input.Apply(solarSystem)
+	if solarSystem.ledOverride == nil {
+		t.Fatal("input did not take over the wall")
+	}
+	if earth := input.planetOffset[Earth]; solarSystem.ledOverride[earth] != (RGBA{B: 255, A: 255}) {
+		t.Errorf("first led of Earth is %v", solarSystem.ledOverride[earth])
+	}
+
+	// the Sun's source stops while Earth's keeps streaming
+	input.setUniverse(1, nil)
+	override := solarSystem.ledOverride
+	input.Apply(solarSystem)
+	if solarSystem.ledOverride == nil {
+		t.Fatal("one universe terminating handed back the whole wall")
+	}
+	if &solarSystem.ledOverride[0] != &override[0] {
+		t.Error("override was reallocated")
+	}
+
+	input.setUniverse(4, nil)
+	input.Apply(solarSystem)
+	if solarSystem.ledOverride != nil {
+		t.Error("wall not handed back once every universe terminated")
+	}
+}
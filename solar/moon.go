@@ -0,0 +1,41 @@
+package solar
+
+import (
+	"math"
+	"time"
+)
+
+// moonPhaseNames names of the phases, each centered on an eighth of the lunar month starting from new
+var moonPhaseNames = [...]string{"New Moon", "Waxing Crescent", "First Quarter", "Waxing Gibbous", "Full Moon", "Waning Gibbous", "Last Quarter", "Waning Crescent"}
+
+// MoonPhase where the moon is in its month at t, age is 0 at new moon through 0.5 at full back to 1, and the fraction of the disc that is lit
+// Uses the low precision series from Meeus, Astronomical Algorithms chapter 48, good to a fraction of a percent
+func MoonPhase(t time.Time) (age float64, illuminated float64) {
+	centuries := (julianDate(t) - 2451545.0) / 36525
+	radians := func(degrees float64) float64 { return degrees * math.Pi / 180 }
+
+	elongation := radians(297.8501921 + 445267.1114034*centuries)
+	sunAnomaly := radians(357.5291092 + 35999.0502909*centuries)
+	moonAnomaly := radians(134.9633964 + 477198.8675055*centuries)
+
+	// phase angle, the angle between the sun and earth as seen from the moon, in degrees
+	phaseAngle := 180 - elongation*180/math.Pi -
+		6.289*math.Sin(moonAnomaly) +
+		2.100*math.Sin(sunAnomaly) -
+		1.274*math.Sin(2*elongation-moonAnomaly) -
+		0.658*math.Sin(2*elongation) -
+		0.214*math.Sin(2*moonAnomaly) -
+		0.110*math.Sin(elongation)
+
+	illuminated = (1 + math.Cos(radians(phaseAngle))) / 2
+	age = math.Mod(180-phaseAngle, 360) / 360
+	if age < 0 {
+		age++
+	}
+	return age, illuminated
+}
+
+// MoonPhaseName name of the phase for an age from MoonPhase
+func MoonPhaseName(age float64) string {
+	return moonPhaseNames[int(math.Floor(age*8+0.5))%8]
+}
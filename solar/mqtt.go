@@ -0,0 +1,268 @@
+package solar
+
+import (
+	"encoding/json"
+	"log"
+	"math"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// how often the state is checked for changes made by the api, schedule or lighting console
+const mqttStateInterval = time.Second
+
+// mqttLightConfig Home Assistant discovery config of a json schema mqtt light
+type mqttLightConfig struct {
+	Name                string     `json:"name"`
+	UniqueID            string     `json:"unique_id"`
+	Schema              string     `json:"schema"`
+	CommandTopic        string     `json:"command_topic"`
+	StateTopic          string     `json:"state_topic"`
+	AvailabilityTopic   string     `json:"availability_topic"`
+	Brightness          bool       `json:"brightness"`
+	BrightnessScale     int        `json:"brightness_scale"`
+	Effect              bool       `json:"effect"`
+	EffectList          []string   `json:"effect_list"`
+	SupportedColorModes []string   `json:"supported_color_modes"`
+	Device              mqttDevice `json:"device"`
+}
+
+// mqttDevice the device the light belongs to in Home Assistant
+type mqttDevice struct {
+	Identifiers  []string `json:"identifiers"`
+	Name         string   `json:"name"`
+	Manufacturer string   `json:"manufacturer"`
+	Model        string   `json:"model"`
+}
+
+// mqttColor rgb color of the light
+type mqttColor struct {
+	R uint8 `json:"r"`
+	G uint8 `json:"g"`
+	B uint8 `json:"b"`
+}
+
+// mqttLightState state published to the state topic, brightness is percent
+type mqttLightState struct {
+	State      string    `json:"state"`
+	Brightness int       `json:"brightness"`
+	Effect     string    `json:"effect"`
+	ColorMode  string    `json:"color_mode"`
+	Color      mqttColor `json:"color"`
+}
+
+// mqttLightCommand command received on the command topic, anything missing is left as it is
+type mqttLightCommand struct {
+	State      string     `json:"state"`
+	Brightness *float64   `json:"brightness"`
+	Effect     string     `json:"effect"`
+	Color      *mqttColor `json:"color"`
+	Transition *float64   `json:"transition"`
+}
+
+// MQTTBridge shows the wall to Home Assistant as a light, with brightness, the scenes as effects and an rgb color for the color scene
+type MQTTBridge struct {
+
+	// system commands are applied to and state is read from
+	solarSystem *System
+
+	// connection to the broker
+	client mqtt.Client
+
+	// identifies the wall in topics and to Home Assistant
+	nodeID string
+
+	// topic Home Assistant watches for discovery configs, usually homeassistant
+	discoveryPrefix string
+
+	// percent to return to when turned on without a brightness
+	lastLevel float64
+
+	// state last published, so it is only sent again when it changes, guarded by the system lock
+	published mqttLightState
+
+	// closed to stop publishing state
+	done chan struct{}
+}
+
+// NewMQTTBridge connect to broker, such as tcp://localhost:1883, and announce the wall to Home Assistant as nodeID
+func NewMQTTBridge(solarSystem *System, broker string, username string, password string, nodeID string, discoveryPrefix string) (*MQTTBridge, error) {
+	bridge := &MQTTBridge{
+		solarSystem:     solarSystem,
+		nodeID:          nodeID,
+		discoveryPrefix: discoveryPrefix,
+		lastLevel:       100,
+		done:            make(chan struct{}),
+	}
+
+	options := mqtt.NewClientOptions().
+		AddBroker(broker).
+		SetClientID(nodeID).
+		SetUsername(username).
+		SetPassword(password).
+		SetAutoReconnect(true).
+		SetWill(bridge.topic("availability"), "offline", 1, true).
+		SetOnConnectHandler(bridge.connected)
+
+	bridge.client = mqtt.NewClient(options)
+	if token := bridge.client.Connect(); token.Wait() && token.Error() != nil {
+		return nil, token.Error()
+	}
+
+	go bridge.publishChanges()
+
+	log.Print("Connected to mqtt broker ", broker, " as ", nodeID)
+	return bridge, nil
+}
+
+// Close mark the wall unavailable and disconnect
+func (bridge *MQTTBridge) Close() {
+	close(bridge.done)
+	bridge.client.Publish(bridge.topic("availability"), 1, true, "offline").Wait()
+	bridge.client.Disconnect(250)
+}
+
+// topic of the wall ending in name
+func (bridge *MQTTBridge) topic(name string) string {
+	return bridge.nodeID + "/" + name
+}
+
+// connected subscribe and announce the wall, on the first connection and every reconnection
+func (bridge *MQTTBridge) connected(client mqtt.Client) {
+	client.Subscribe(bridge.topic("set"), 1, bridge.command)
+
+	// Home Assistant asks for discovery again when it restarts
+	client.Subscribe(bridge.discoveryPrefix+"/status", 1, func(client mqtt.Client, message mqtt.Message) {
+		if string(message.Payload()) == "online" {
+			bridge.announce()
+		}
+	})
+
+	bridge.announce()
+}
+
+// announce publish the discovery config, availability and state
+func (bridge *MQTTBridge) announce() {
+	config, _ := json.Marshal(mqttLightConfig{
+		Name:                "Solar System Wall",
+		UniqueID:            bridge.nodeID,
+		Schema:              "json",
+		CommandTopic:        bridge.topic("set"),
+		StateTopic:          bridge.topic("state"),
+		AvailabilityTopic:   bridge.topic("availability"),
+		Brightness:          true,
+		BrightnessScale:     100,
+		Effect:              true,
+		EffectList:          SceneNames(),
+		SupportedColorModes: []string{"rgb"},
+		Device: mqttDevice{
+			Identifiers:  []string{bridge.nodeID},
+			Name:         "Solar System Wall",
+			Manufacturer: "brandonagr",
+			Model:        "solarsystemwall",
+		},
+	})
+	bridge.client.Publish(bridge.discoveryPrefix+"/light/"+bridge.nodeID+"/config", 1, true, config)
+	bridge.client.Publish(bridge.topic("availability"), 1, true, "online")
+
+	bridge.solarSystem.Lock()
+	bridge.publish(bridge.state())
+	bridge.solarSystem.Unlock()
+}
+
+// command apply a json command from Home Assistant to the wall
+func (bridge *MQTTBridge) command(client mqtt.Client, message mqtt.Message) {
+	var command mqttLightCommand
+	if err := json.Unmarshal(message.Payload(), &command); err != nil {
+		log.Print("Ignoring mqtt command: ", err)
+		return
+	}
+
+	bridge.solarSystem.Lock()
+	now := time.Now()
+
+	ramp := 1.0
+	if command.Transition != nil {
+		ramp = math.Max(0, *command.Transition)
+	}
+
+	if command.Effect != "" {
+		if err := bridge.solarSystem.SetScene(command.Effect); err != nil {
+			log.Print("Ignoring mqtt command: ", err)
+		} else {
+			bridge.solarSystem.SceneSchedule().SetManual(now)
+		}
+	}
+
+	if command.Color != nil {
+		bridge.solarSystem.SetFillColor(RGBA{R: command.Color.R, G: command.Color.G, B: command.Color.B})
+		if command.Effect == "" && bridge.solarSystem.SceneName() != "color" {
+			if err := bridge.solarSystem.SetScene("color"); err != nil {
+				log.Print("Ignoring mqtt command: ", err)
+			} else {
+				bridge.solarSystem.SceneSchedule().SetManual(now)
+			}
+		}
+	}
+
+	brightness := bridge.solarSystem.Brightness()
+	switch {
+	case command.State == "OFF":
+		if level := brightness.State().Target; level > 0 {
+			bridge.lastLevel = level
+		}
+		brightness.Set(0, ramp, now)
+	case command.Brightness != nil:
+		brightness.Set(*command.Brightness, ramp, now)
+	case command.State == "ON" && brightness.State().Target == 0:
+		brightness.Set(bridge.lastLevel, ramp, now)
+	}
+
+	bridge.publish(bridge.state())
+	bridge.solarSystem.Unlock()
+}
+
+// state of the wall as Home Assistant sees it, the system must be locked
+func (bridge *MQTTBridge) state() mqttLightState {
+	level := bridge.solarSystem.Brightness().State().Target
+	fill := bridge.solarSystem.FillColor()
+
+	state := mqttLightState{
+		State:      "OFF",
+		Brightness: int(math.Round(level)),
+		Effect:     bridge.solarSystem.SceneName(),
+		ColorMode:  "rgb",
+		Color:      mqttColor{R: fill.R, G: fill.G, B: fill.B},
+	}
+	if level > 0 {
+		state.State = "ON"
+	}
+	return state
+}
+
+// publish state, retained so Home Assistant sees it after restarting, the system must be locked
+func (bridge *MQTTBridge) publish(state mqttLightState) {
+	payload, _ := json.Marshal(state)
+	bridge.client.Publish(bridge.topic("state"), 1, true, payload)
+	bridge.published = state
+}
+
+// publishChanges publish the state whenever it is changed by something other than a command, until Close
+func (bridge *MQTTBridge) publishChanges() {
+	ticks := time.NewTicker(mqttStateInterval)
+	defer ticks.Stop()
+
+	for {
+		select {
+		case <-bridge.done:
+			return
+		case <-ticks.C:
+			bridge.solarSystem.Lock()
+			if state := bridge.state(); state != bridge.published {
+				bridge.publish(state)
+			}
+			bridge.solarSystem.Unlock()
+		}
+	}
+}
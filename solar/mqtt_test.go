@@ -0,0 +1,308 @@
+package solar
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// testBroker in process mqtt 3.1.1 broker with just enough for the bridge, exact topic subscriptions, retained messages and wills
+type testBroker struct {
+	listener net.Listener
+
+	// guards subscriptions and retained
+	lock sync.Mutex
+
+	// topics each connection subscribed to
+	subscriptions map[net.Conn][]string
+
+	// last retained payload of each topic
+	retained map[string][]byte
+}
+
+// newTestBroker listen on a free local port until the test ends
+func newTestBroker(t *testing.T) *testBroker {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	broker := &testBroker{
+		listener:      listener,
+		subscriptions: map[net.Conn][]string{},
+		retained:      map[string][]byte{},
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go broker.serve(conn)
+		}
+	}()
+	return broker
+}
+
+// url of the broker for paho
+func (broker *testBroker) url() string {
+	return "tcp://" + broker.listener.Addr().String()
+}
+
+// serve one client until it disconnects, publishing its will if it drops
+func (broker *testBroker) serve(conn net.Conn) {
+	reader := bufio.NewReader(conn)
+	var willTopic string
+	var willPayload []byte
+	defer func() {
+		broker.lock.Lock()
+		delete(broker.subscriptions, conn)
+		broker.lock.Unlock()
+		conn.Close()
+		if willTopic != "" {
+			broker.deliver(willTopic, willPayload, true)
+		}
+	}()
+
+	for {
+		header, body, err := readMQTTPacket(reader)
+		if err != nil {
+			return
+		}
+
+		switch header >> 4 {
+		case 1: // CONNECT
+			flags := body[7]
+			_, rest := readMQTTString(body[10:]) // client id
+			if flags&0x04 != 0 {
+				var topic, payload []byte
+				topic, rest = readMQTTString(rest)
+				payload, _ = readMQTTString(rest)
+				willTopic, willPayload = string(topic), payload
+			}
+			conn.Write([]byte{0x20, 2, 0, 0})
+		case 3: // PUBLISH
+			topic, rest := readMQTTString(body)
+			if (header>>1)&3 > 0 {
+				conn.Write([]byte{0x40, 2, rest[0], rest[1]})
+				rest = rest[2:]
+			}
+			broker.deliver(string(topic), append([]byte{}, rest...), header&1 != 0)
+		case 8: // SUBSCRIBE
+			ack := []byte{body[0], body[1]}
+			var topics []string
+			for rest := body[2:]; len(rest) > 0; rest = rest[1:] {
+				var topic []byte
+				topic, rest = readMQTTString(rest)
+				topics = append(topics, string(topic))
+				ack = append(ack, 0)
+			}
+			conn.Write(mqttPacket(0x90, ack))
+
+			broker.lock.Lock()
+			broker.subscriptions[conn] = append(broker.subscriptions[conn], topics...)
+			for _, topic := range topics {
+				if payload, ok := broker.retained[topic]; ok {
+					conn.Write(mqttPacket(0x31, append(mqttString(topic), payload...)))
+				}
+			}
+			broker.lock.Unlock()
+		case 10: // UNSUBSCRIBE
+			broker.lock.Lock()
+			for rest := body[2:]; len(rest) > 0; {
+				var topic []byte
+				topic, rest = readMQTTString(rest)
+				var kept []string
+				for _, subscribed := range broker.subscriptions[conn] {
+					if subscribed != string(topic) {
+						kept = append(kept, subscribed)
+					}
+				}
+				broker.subscriptions[conn] = kept
+			}
+			broker.lock.Unlock()
+			conn.Write([]byte{0xb0, 2, body[0], body[1]})
+		case 12: // PINGREQ
+			conn.Write([]byte{0xd0, 0})
+		case 14: // DISCONNECT
+			willTopic = ""
+			return
+		}
+	}
+}
+
+// deliver payload to every subscriber of topic
+func (broker *testBroker) deliver(topic string, payload []byte, retain bool) {
+	broker.lock.Lock()
+	defer broker.lock.Unlock()
+
+	if retain {
+		broker.retained[topic] = payload
+	}
+	for conn, topics := range broker.subscriptions {
+		for _, subscribed := range topics {
+			if subscribed == topic {
+				conn.Write(mqttPacket(0x30, append(mqttString(topic), payload...)))
+			}
+		}
+	}
+}
+
+// readMQTTPacket read the fixed header and body of one packet
+func readMQTTPacket(reader *bufio.Reader) (header byte, body []byte, err error) {
+	header, err = reader.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	length, multiplier := 0, 1
+	for {
+		digit, err := reader.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		length += int(digit&0x7f) * multiplier
+		multiplier *= 128
+		if digit&0x80 == 0 {
+			break
+		}
+	}
+
+	body = make([]byte, length)
+	_, err = io.ReadFull(reader, body)
+	return header, body, err
+}
+
+// mqttPacket header and body with the remaining length between them
+func mqttPacket(header byte, body []byte) []byte {
+	packet := []byte{header}
+	length := len(body)
+	for {
+		digit := byte(length % 128)
+		length /= 128
+		if length > 0 {
+			digit |= 0x80
+		}
+		packet = append(packet, digit)
+		if length == 0 {
+			break
+		}
+	}
+	return append(packet, body...)
+}
+
+// mqttString length prefixed text
+func mqttString(text string) []byte {
+	return append([]byte{byte(len(text) >> 8), byte(len(text))}, text...)
+}
+
+// readMQTTString the length prefixed bytes at the start of data and what follows them
+func readMQTTString(data []byte) (text []byte, rest []byte) {
+	length := int(binary.BigEndian.Uint16(data))
+	return data[2 : 2+length], data[2+length:]
+}
+
+// receiveJSON subscribe client to topic and decode the first message into value
+func receiveJSON(t *testing.T, client mqtt.Client, topic string, value interface{}) {
+	messages := make(chan []byte, 1)
+	client.Subscribe(topic, 1, func(client mqtt.Client, message mqtt.Message) {
+		select {
+		case messages <- message.Payload():
+		default:
+		}
+	}).Wait()
+	defer client.Unsubscribe(topic)
+
+	select {
+	case payload := <-messages:
+		if err := json.Unmarshal(payload, value); err != nil {
+			t.Fatalf("%s: %v", topic, err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatalf("nothing retained on %s", topic)
+	}
+}
+
+// waitFor poll condition with the system locked until it holds
+func waitFor(t *testing.T, solarSystem *System, what string, condition func() bool) {
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		solarSystem.Lock()
+		done := condition()
+		solarSystem.Unlock()
+		if done {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %s", what)
+}
+
+func TestMQTTBridge(t *testing.T) {
+	broker := newTestBroker(t)
+	solarSystem := DefaultSystem()
+
+	bridge, err := NewMQTTBridge(solarSystem, broker.url(), "", "", "wall", "homeassistant")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bridge.Close()
+
+	homeAssistant := mqtt.NewClient(mqtt.NewClientOptions().AddBroker(broker.url()).SetClientID("homeassistant"))
+	if token := homeAssistant.Connect(); token.Wait() && token.Error() != nil {
+		t.Fatal(token.Error())
+	}
+	defer homeAssistant.Disconnect(0)
+
+	var config mqttLightConfig
+	receiveJSON(t, homeAssistant, "homeassistant/light/wall/config", &config)
+	if config.UniqueID != "wall" || config.Schema != "json" || config.CommandTopic != "wall/set" || config.StateTopic != "wall/state" {
+		t.Errorf("discovery config %+v", config)
+	}
+	if len(config.SupportedColorModes) != 1 || config.SupportedColorModes[0] != "rgb" || len(config.EffectList) != len(SceneNames()) {
+		t.Errorf("discovery config modes %v, effects %v", config.SupportedColorModes, config.EffectList)
+	}
+
+	var state mqttLightState
+	receiveJSON(t, homeAssistant, "wall/state", &state)
+	want := mqttLightState{State: "ON", Brightness: 100, Effect: "orrery", ColorMode: "rgb", Color: mqttColor{R: 255, G: 180, B: 100}}
+	if state != want {
+		t.Errorf("state %+v, want %+v", state, want)
+	}
+
+	send := func(command string) {
+		homeAssistant.Publish("wall/set", 1, false, command).Wait()
+	}
+
+	send(`{"state":"OFF"}`)
+	waitFor(t, solarSystem, "off", func() bool { return solarSystem.Brightness().State().Target == 0 })
+
+	send(`{"brightness":40}`)
+	waitFor(t, solarSystem, "brightness 40", func() bool { return solarSystem.Brightness().State().Target == 40 })
+
+	send(`{"effect":"plasma"}`)
+	waitFor(t, solarSystem, "plasma", func() bool { return solarSystem.SceneName() == "plasma" })
+
+	send(`{"color":{"r":0,"g":64,"b":255}}`)
+	waitFor(t, solarSystem, "blue", func() bool {
+		return solarSystem.FillColor() == RGBA{R: 0, G: 64, B: 255, A: 255} && solarSystem.SceneName() == "color"
+	})
+
+	// the state is published after the command is applied, so wait for it to catch up
+	want = mqttLightState{State: "ON", Brightness: 40, Effect: "color", ColorMode: "rgb", Color: mqttColor{R: 0, G: 64, B: 255}}
+	deadline := time.Now().Add(2 * time.Second)
+	for receiveJSON(t, homeAssistant, "wall/state", &state); state != want; receiveJSON(t, homeAssistant, "wall/state", &state) {
+		if time.Now().After(deadline) {
+			t.Fatalf("state after commands %+v, want %+v", state, want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,105 @@
+package solar
+
+import (
+	"math"
+)
+
+// simplex noise after Stefan Gustavson's "Simplex noise demystified", using Ken Perlin's reference permutation
+
+// noiseGradients directions to the midpoints of the edges of a cube
+var noiseGradients = [12][3]float64{
+	{1, 1, 0}, {-1, 1, 0}, {1, -1, 0}, {-1, -1, 0},
+	{1, 0, 1}, {-1, 0, 1}, {1, 0, -1}, {-1, 0, -1},
+	{0, 1, 1}, {0, -1, 1}, {0, 1, -1}, {0, -1, -1},
+}
+
+// noisePermutation the reference permutation repeated twice to avoid wrapping indexes
+var noisePermutation = func() [512]uint8 {
+	base := [256]uint8{151, 160, 137, 91, 90, 15,
+		131, 13, 201, 95, 96, 53, 194, 233, 7, 225, 140, 36, 103, 30, 69, 142, 8, 99, 37, 240, 21, 10, 23,
+		190, 6, 148, 247, 120, 234, 75, 0, 26, 197, 62, 94, 252, 219, 203, 117, 35, 11, 32, 57, 177, 33,
+		88, 237, 149, 56, 87, 174, 20, 125, 136, 171, 168, 68, 175, 74, 165, 71, 134, 139, 48, 27, 166,
+		77, 146, 158, 231, 83, 111, 229, 122, 60, 211, 133, 230, 220, 105, 92, 41, 55, 46, 245, 40, 244,
+		102, 143, 54, 65, 25, 63, 161, 1, 216, 80, 73, 209, 76, 132, 187, 208, 89, 18, 169, 200, 196,
+		135, 130, 116, 188, 159, 86, 164, 100, 109, 198, 173, 186, 3, 64, 52, 217, 226, 250, 124, 123,
+		5, 202, 38, 147, 118, 126, 255, 82, 85, 212, 207, 206, 59, 227, 47, 16, 58, 17, 182, 189, 28, 42,
+		223, 183, 170, 213, 119, 248, 152, 2, 44, 154, 163, 70, 221, 153, 101, 155, 167, 43, 172, 9,
+		129, 22, 39, 253, 19, 98, 108, 110, 79, 113, 224, 232, 178, 185, 112, 104, 218, 246, 97, 228,
+		251, 34, 242, 193, 238, 210, 144, 12, 191, 179, 162, 241, 81, 51, 145, 235, 249, 14, 239, 107,
+		49, 192, 214, 31, 181, 199, 106, 157, 184, 84, 204, 176, 115, 121, 50, 45, 127, 4, 150, 254,
+		138, 236, 205, 93, 222, 114, 67, 29, 24, 72, 243, 141, 128, 195, 78, 66, 215, 61, 156, 180}
+
+	var permutation [512]uint8
+	for i := range permutation {
+		permutation[i] = base[i&255]
+	}
+	return permutation
+}()
+
+// SimplexNoise3 smoothly varying noise at a point, roughly -1 to 1
+func SimplexNoise3(x, y, z float64) float64 {
+	const skew, unskew = 1.0 / 3.0, 1.0 / 6.0
+
+	// find the simplex cell containing the point
+	s := (x + y + z) * skew
+	i, j, k := math.Floor(x+s), math.Floor(y+s), math.Floor(z+s)
+	t := (i + j + k) * unskew
+	x0, y0, z0 := x-(i-t), y-(j-t), z-(k-t)
+
+	// which of the six tetrahedra in the cube the point is in
+	var i1, j1, k1, i2, j2, k2 float64
+	switch {
+	case x0 >= y0 && y0 >= z0:
+		i1, j1, k1, i2, j2, k2 = 1, 0, 0, 1, 1, 0
+	case x0 >= y0 && x0 >= z0:
+		i1, j1, k1, i2, j2, k2 = 1, 0, 0, 1, 0, 1
+	case x0 >= y0:
+		i1, j1, k1, i2, j2, k2 = 0, 0, 1, 1, 0, 1
+	case y0 < z0:
+		i1, j1, k1, i2, j2, k2 = 0, 0, 1, 0, 1, 1
+	case x0 < z0:
+		i1, j1, k1, i2, j2, k2 = 0, 1, 0, 0, 1, 1
+	default:
+		i1, j1, k1, i2, j2, k2 = 0, 1, 0, 1, 1, 0
+	}
+
+	corners := [4][3]float64{
+		{x0, y0, z0},
+		{x0 - i1 + unskew, y0 - j1 + unskew, z0 - k1 + unskew},
+		{x0 - i2 + 2*unskew, y0 - j2 + 2*unskew, z0 - k2 + 2*unskew},
+		{x0 - 1 + 3*unskew, y0 - 1 + 3*unskew, z0 - 1 + 3*unskew},
+	}
+	offsets := [4][3]int{{0, 0, 0}, {int(i1), int(j1), int(k1)}, {int(i2), int(j2), int(k2)}, {1, 1, 1}}
+
+	ii, jj, kk := int(i)&255, int(j)&255, int(k)&255
+	total := 0.0
+	for corner, position := range corners {
+		falloff := 0.6 - position[0]*position[0] - position[1]*position[1] - position[2]*position[2]
+		if falloff <= 0 {
+			continue
+		}
+		offset := offsets[corner]
+		hash := noisePermutation[ii+offset[0]+int(noisePermutation[jj+offset[1]+int(noisePermutation[kk+offset[2]])])] % 12
+		gradient := noiseGradients[hash]
+		falloff *= falloff
+		total += falloff * falloff * (gradient[0]*position[0] + gradient[1]*position[1] + gradient[2]*position[2])
+	}
+
+	// scale to roughly -1 to 1
+	return 32 * total
+}
+
+// FractalNoise3 sum octaves of simplex noise, each at twice the frequency and half the strength of the last, roughly -1 to 1
+func FractalNoise3(x, y, z float64, octaves int) float64 {
+	total, strength, frequency, normalize := 0.0, 1.0, 1.0, 0.0
+	for octave := 0; octave < octaves; octave++ {
+		total += SimplexNoise3(x*frequency, y*frequency, z*frequency) * strength
+		normalize += strength
+		strength /= 2
+		frequency *= 2
+	}
+	if normalize == 0 {
+		return 0
+	}
+	return total / normalize
+}
@@ -0,0 +1,314 @@
+package solar
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/geo/r2"
+)
+
+// most notifications that can wait in the queue
+const maxQueuedNotifications = 50
+
+// notificationPatterns every pattern a notification can be drawn with
+var notificationPatterns = map[string]bool{
+	"solid":  true,
+	"pulse":  true,
+	"flash":  true,
+	"ripple": true,
+}
+
+// namedColors colors that can be given by name instead of hex
+var namedColors = map[string]RGBA{
+	"red":    {R: 255, G: 0, B: 0, A: 255},
+	"green":  {R: 0, G: 255, B: 0, A: 255},
+	"blue":   {R: 0, G: 0, B: 255, A: 255},
+	"white":  {R: 255, G: 255, B: 255, A: 255},
+	"yellow": {R: 255, G: 200, B: 0, A: 255},
+	"orange": {R: 255, G: 100, B: 0, A: 255},
+	"purple": {R: 160, G: 0, B: 255, A: 255},
+	"cyan":   {R: 0, G: 255, B: 255, A: 255},
+}
+
+// Notification a temporary overlay such as a build result, drawn on top of whatever scene is showing
+type Notification struct {
+	Color RGBA
+
+	// solid, pulse, flash or ripple
+	Pattern string
+
+	// seconds to show for
+	Duration float64
+
+	// a higher priority notification replaces a lower one that is showing, which goes back in the queue
+	Priority int
+
+	// planet the notification is drawn on or ripples out from, the whole wall when not set
+	Planet      PlanetIndex
+	PlanetIsSet bool
+
+	// seconds the notification may wait in the queue before it is dropped
+	Expire float64
+}
+
+// NotificationInfo json describing a notification in /api/notify
+type NotificationInfo struct {
+	Color     string  `json:"color"`
+	Pattern   string  `json:"pattern"`
+	Priority  int     `json:"priority"`
+	Planet    string  `json:"planet,omitempty"`
+	Remaining float64 `json:"remaining"`
+}
+
+// NotificationState json returned by /api/notify
+type NotificationState struct {
+	Showing *NotificationInfo  `json:"showing"`
+	Queued  []NotificationInfo `json:"queued"`
+}
+
+// ParseColor a color as hex such as #00ff00 or by name such as green
+func ParseColor(text string) (RGBA, error) {
+	text = strings.ToLower(strings.TrimSpace(text))
+	if color, ok := namedColors[text]; ok {
+		return color, nil
+	}
+
+	hex := strings.TrimPrefix(text, "#")
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if len(hex) != 6 || err != nil {
+		return RGBA{}, fmt.Errorf("color %q must be hex like #00ff00 or one of red, green, blue, white, yellow, orange, purple, cyan", text)
+	}
+	return RGBA{R: uint8(value >> 16), G: uint8(value >> 8), B: uint8(value), A: 255}, nil
+}
+
+// queuedNotification a notification waiting to show
+type queuedNotification struct {
+	notification Notification
+
+	// when it was queued, earlier ones of the same priority show first
+	queued time.Time
+
+	// dropped if still waiting after this
+	expires time.Time
+}
+
+// NotificationQueue shows notifications one at a time in priority order
+type NotificationQueue struct {
+
+	// notification on the wall, nil when none is
+	showing *DrawNotification
+
+	// waiting notifications, highest priority first
+	queue []queuedNotification
+}
+
+// NewNotificationQueue an empty queue
+func NewNotificationQueue() *NotificationQueue {
+	return &NotificationQueue{}
+}
+
+// Add show notification now if it outranks the one showing, otherwise queue it
+func (queue *NotificationQueue) Add(notification Notification, now time.Time, solarSystem *System) error {
+	if !notificationPatterns[notification.Pattern] {
+		return fmt.Errorf("unknown pattern %q", notification.Pattern)
+	}
+	if notification.Duration <= 0 {
+		return fmt.Errorf("duration must be positive")
+	}
+
+	if queue.showing == nil {
+		queue.show(notification, solarSystem)
+		return nil
+	}
+
+	if notification.Priority > queue.showing.notification.Priority {
+		// put the one showing back with the time it had left
+		preempted := queue.showing.notification
+		preempted.Duration -= queue.showing.elapsed
+		queue.showing.stop()
+		queue.enqueue(preempted, now)
+
+		queue.show(notification, solarSystem)
+		return nil
+	}
+
+	if len(queue.queue) >= maxQueuedNotifications {
+		return fmt.Errorf("too many notifications waiting")
+	}
+	queue.enqueue(notification, now)
+	return nil
+}
+
+// enqueue notification keeping the queue in priority order
+func (queue *NotificationQueue) enqueue(notification Notification, now time.Time) {
+	queue.queue = append(queue.queue, queuedNotification{
+		notification: notification,
+		queued:       now,
+		expires:      now.Add(time.Duration(notification.Expire * float64(time.Second))),
+	})
+	sort.SliceStable(queue.queue, func(i, j int) bool {
+		return queue.queue[i].notification.Priority > queue.queue[j].notification.Priority
+	})
+}
+
+// show notification on top of solarSystem
+func (queue *NotificationQueue) show(notification Notification, solarSystem *System) {
+	queue.showing = NewNotificationOverlay(notification, solarSystem)
+	solarSystem.AddDrawable(queue.showing)
+}
+
+// Clear remove every notification
+func (queue *NotificationQueue) Clear() {
+	if queue.showing != nil {
+		queue.showing.stop()
+		queue.showing = nil
+	}
+	queue.queue = nil
+}
+
+// Animate drop expired notifications and show the next once the current one is done
+func (queue *NotificationQueue) Animate(now time.Time, solarSystem *System) {
+	waiting := queue.queue[:0]
+	for _, queued := range queue.queue {
+		if now.Before(queued.expires) {
+			waiting = append(waiting, queued)
+		}
+	}
+	queue.queue = waiting
+
+	if queue.showing != nil && queue.showing.done() {
+		queue.showing = nil
+	}
+	if queue.showing == nil && len(queue.queue) > 0 {
+		next := queue.queue[0]
+		queue.queue = queue.queue[1:]
+		queue.show(next.notification, solarSystem)
+	}
+}
+
+// reattach put the showing notification back on top after the scene replaced every drawable
+func (queue *NotificationQueue) reattach(solarSystem *System) {
+	if queue.showing != nil {
+		solarSystem.AddDrawable(queue.showing)
+	}
+}
+
+// State the notification showing and those waiting
+func (queue *NotificationQueue) State() NotificationState {
+	state := NotificationState{Queued: []NotificationInfo{}}
+	if queue.showing != nil {
+		info := queue.showing.notification.info(queue.showing.notification.Duration - queue.showing.elapsed)
+		state.Showing = &info
+	}
+	for _, queued := range queue.queue {
+		state.Queued = append(state.Queued, queued.notification.info(queued.notification.Duration))
+	}
+	return state
+}
+
+// info json description of the notification with remaining seconds left to show
+func (notification Notification) info(remaining float64) NotificationInfo {
+	info := NotificationInfo{
+		Color:     fmt.Sprintf("#%02x%02x%02x", notification.Color.R, notification.Color.G, notification.Color.B),
+		Pattern:   notification.Pattern,
+		Priority:  notification.Priority,
+		Remaining: math.Max(0, remaining),
+	}
+	if notification.PlanetIsSet {
+		info.Planet = notification.Planet.String()
+	}
+	return info
+}
+
+// DrawNotification renders a notification on top of the scene until its duration is up
+type DrawNotification struct {
+	notification Notification
+
+	// where the pattern is centered, and the radius it is limited to for a single planet
+	center r2.Point
+	radius float64
+
+	// seconds shown so far
+	elapsed float64
+
+	// replaced by a higher priority notification or cleared
+	stopped bool
+}
+
+var _ Drawable = &DrawNotification{}
+
+// NewNotificationOverlay Construct the overlay drawing notification
+func NewNotificationOverlay(notification Notification, solarSystem *System) *DrawNotification {
+	overlay := &DrawNotification{
+		notification: notification,
+		center:       r2.Point{X: WallWidth / 2, Y: WallHeight / 2},
+		radius:       math.Hypot(WallWidth, WallHeight),
+	}
+	if notification.PlanetIsSet {
+		planet := solarSystem.planets[notification.Planet]
+		overlay.center = planet.position
+		if notification.Pattern != "ripple" {
+			overlay.radius = planet.radius
+		}
+	}
+	return overlay
+}
+
+// stop drawing, the overlay removes itself on the next Animate
+func (overlay *DrawNotification) stop() {
+	overlay.stopped = true
+}
+
+// done if the overlay has finished or was stopped
+func (overlay *DrawNotification) done() bool {
+	return overlay.stopped || overlay.elapsed >= overlay.notification.Duration
+}
+
+// Affects returns bounding circle check
+func (overlay *DrawNotification) Affects(position r2.Point, radius float64) bool {
+	return !overlay.stopped && overlay.center.Sub(position).Norm() < radius+overlay.radius
+}
+
+// ColorAt Returns the color at position blended on top of baseColor
+func (overlay *DrawNotification) ColorAt(position r2.Point, baseColor RGBA) (color RGBA) {
+	distance := overlay.center.Sub(position).Norm()
+	if overlay.stopped || distance > overlay.radius {
+		return baseColor
+	}
+
+	// fade in and out over a fraction of a second so the scene is not cut abruptly
+	fade := math.Min(1, math.Min(overlay.elapsed, overlay.notification.Duration-overlay.elapsed)/0.3)
+
+	strength := 1.0
+	switch overlay.notification.Pattern {
+	case "pulse":
+		strength = 0.5 - 0.5*math.Cos(2*math.Pi*overlay.elapsed)
+	case "flash":
+		if math.Mod(overlay.elapsed, 0.25) >= 0.125 {
+			strength = 0
+		}
+	case "ripple":
+		// a ring leaves the center every 1.5 seconds
+		ringRadius := math.Mod(overlay.elapsed, 1.5) * 60
+		strength = math.Max(0, 1-math.Abs(distance-ringRadius)/6)
+	}
+
+	color = overlay.notification.Color
+	color.A = uint8(math.Max(0, fade*strength) * 255)
+	return color.BlendWith(baseColor)
+}
+
+// ZIndex on top of every scene
+func (overlay *DrawNotification) ZIndex() ZIndex {
+	return 1000
+}
+
+// Animate notification
+func (overlay *DrawNotification) Animate(dt float64) bool {
+	overlay.elapsed += dt
+	return !overlay.done()
+}
@@ -0,0 +1,184 @@
+package solar
+
+import (
+	"math"
+	"sort"
+)
+
+// Palette a gradient blended in a color space, optionally wrapping so the last color blends back into the first
+type Palette struct {
+	gradient Gradient
+
+	// space the stops are blended in
+	space ColorSpace
+
+	// positions wrap around, for palettes sampled by angle or time that loop
+	cyclic bool
+}
+
+// NewPalette a palette through colors spaced evenly, blended in space
+func NewPalette(space ColorSpace, colors ...RGBA) *Palette {
+	return &Palette{gradient: NewGradient(colors...), space: space}
+}
+
+// NewGradientPalette a palette through the stops of gradient, blended in space
+func NewGradientPalette(space ColorSpace, gradient Gradient) *Palette {
+	stops := append(Gradient(nil), gradient...)
+	sort.SliceStable(stops, func(i, j int) bool { return stops[i].Position < stops[j].Position })
+	return &Palette{gradient: stops, space: space}
+}
+
+// Cyclic wrap positions so the palette loops, the last color blending back into the first
+// The last stop should be before 1 so there is room to blend back
+func (palette *Palette) Cyclic() *Palette {
+	palette.cyclic = true
+	return palette
+}
+
+// At color at position, 0 to 1 covers the palette once
+func (palette *Palette) At(position float64) RGBA {
+	if !palette.cyclic || len(palette.gradient) == 0 {
+		return palette.gradient.blend(position, palette.space)
+	}
+
+	// blend from the last stop back around to the first
+	position -= math.Floor(position)
+	first, last := palette.gradient[0], palette.gradient[len(palette.gradient)-1]
+	gap := first.Position + 1 - last.Position
+	if position < first.Position || position > last.Position {
+		wrapped := position - last.Position
+		if wrapped < 0 {
+			wrapped++
+		}
+		progress := 0.0
+		if gap > 0 {
+			progress = wrapped / gap
+		}
+		return blendColors(last.Color, first.Color, progress, palette.space)
+	}
+	return palette.gradient.blend(position, palette.space)
+}
+
+// Palettes every named palette, for use by scenes and configuration
+var Palettes = map[string]*Palette{
+	"rainbow": NewGradientPalette(BlendHSV, Gradient{
+		{Position: 0, Color: RGBA{R: 255, G: 0, B: 0, A: 255}},
+		{Position: 1.0 / 3, Color: RGBA{R: 0, G: 255, B: 0, A: 255}},
+		{Position: 2.0 / 3, Color: RGBA{R: 0, G: 0, B: 255, A: 255}},
+	}).Cyclic(),
+	"fire": NewPalette(BlendOKLab,
+		RGBA{R: 0, G: 0, B: 0, A: 255},
+		RGBA{R: 180, G: 20, B: 0, A: 255},
+		RGBA{R: 255, G: 140, B: 0, A: 255},
+		RGBA{R: 255, G: 240, B: 160, A: 255},
+	),
+	"ocean": NewPalette(BlendOKLab,
+		RGBA{R: 0, G: 10, B: 40, A: 255},
+		RGBA{R: 0, G: 80, B: 160, A: 255},
+		RGBA{R: 0, G: 200, B: 200, A: 255},
+	),
+	"sunset": NewPalette(BlendOKLab,
+		RGBA{R: 40, G: 0, B: 80, A: 255},
+		RGBA{R: 220, G: 40, B: 100, A: 255},
+		RGBA{R: 255, G: 150, B: 40, A: 255},
+	),
+	"aurora": NewPalette(BlendOKLab,
+		RGBA{R: 0, G: 40, B: 30, A: 255},
+		RGBA{R: 0, G: 255, B: 120, A: 255},
+		RGBA{R: 60, G: 120, B: 255, A: 255},
+		RGBA{R: 200, G: 60, B: 220, A: 255},
+	),
+	"plasma": NewPalette(BlendOKLab,
+		RGBA{R: 0, G: 10, B: 60, A: 255},
+		RGBA{R: 0, G: 90, B: 160, A: 255},
+		RGBA{R: 110, G: 20, B: 150, A: 255},
+		RGBA{R: 255, G: 80, B: 120, A: 255},
+	),
+	"ice": NewPalette(BlendOKLab,
+		RGBA{R: 0, G: 30, B: 80, A: 255},
+		RGBA{R: 120, G: 200, B: 255, A: 255},
+		RGBA{R: 255, G: 255, B: 255, A: 255},
+	),
+}
+
+// PaletteNames names of every palette in alphabetical order
+func PaletteNames() []string {
+	var names []string
+	for name := range Palettes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// PaintBy what picks the position along the palette of a Paint
+type PaintBy int
+
+// Ways to sample a palette
+const (
+	// every led the same color, cycling through the palette over time
+	PaintByTime PaintBy = iota
+
+	// color by distance from the drawable, such as along a line or out from a planet
+	PaintByDistance
+
+	// color by angle around the drawable, such as the direction of a rotating line
+	PaintByAngle
+)
+
+// Paint colors a drawable by sampling a palette instead of using a single color
+type Paint struct {
+	palette *Palette
+
+	// what picks the position along the palette
+	by PaintBy
+
+	// seconds, distance or radians for one pass through the palette
+	period float64
+
+	// position along the palette at zero time, distance or angle
+	offset float64
+
+	// alpha the drawable applies to the palette color, so palettes can be shared between drawables of different strength
+	alpha uint8
+}
+
+// NewPaint sample palette by time, distance or angle, going through it once every period
+func NewPaint(palette *Palette, by PaintBy, period float64) *Paint {
+	return &Paint{palette: palette, by: by, period: period, alpha: 255}
+}
+
+// Offset start position along the palette, 0 to 1
+func (paint *Paint) Offset(offset float64) *Paint {
+	paint.offset = offset
+	return paint
+}
+
+// Alpha scale the opacity of the palette colors
+func (paint *Paint) Alpha(alpha uint8) *Paint {
+	paint.alpha = alpha
+	return paint
+}
+
+// At color for a drawable at time seconds, for a point distance away at angle radians
+func (paint *Paint) At(time float64, distance float64, angle float64) RGBA {
+	sample := time
+	switch paint.by {
+	case PaintByDistance:
+		sample = distance
+	case PaintByAngle:
+		sample = math.Mod(angle, 2*math.Pi)
+		if sample < 0 {
+			sample += 2 * math.Pi
+		}
+	}
+
+	position := paint.offset
+	if paint.period != 0 {
+		position += sample / paint.period
+	}
+
+	color := paint.palette.At(position)
+	color.A = uint8(uint(color.A) * uint(paint.alpha) / 255)
+	return color
+}
@@ -0,0 +1,248 @@
+package solar
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+
+	"github.com/golang/geo/r2"
+)
+
+// Scene fills an empty system with the drawables that make up one look of the wall
+type Scene func(solarSystem *System)
+
+// errSceneHeld returned by SetScene while something such as calibration holds the wall
+var errSceneHeld = errors.New("the scene is held while the wall is calibrating")
+
+// scenes every scene that can be shown, by name
+var scenes = map[string]Scene{
+	"orrery":    orreryScene,
+	"show":      showScene,
+	"starfield": starfieldScene,
+	"sweep":     sweepScene,
+	"comets":    cometsScene,
+	"solarwind": solarWindScene,
+	"plasma":    plasmaScene,
+	"planets":   planetsScene,
+	"clock":     clockScene,
+	"countdown": countdownScene,
+	"timer":     timerScene,
+	"color":     colorScene,
+	"off":       func(solarSystem *System) {},
+}
+
+// SceneNames names of every scene in alphabetical order
+func SceneNames() []string {
+	var names []string
+	for name := range scenes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetScene replace every drawable with the scene called name, unless the schedule is held
+func (solarSystem *System) SetScene(name string) error {
+	scene, ok := scenes[name]
+	if !ok {
+		return fmt.Errorf("unknown scene %q", name)
+	}
+	if solarSystem.scenes != nil && solarSystem.scenes.held {
+		return errSceneHeld
+	}
+
+	solarSystem.ClearDrawables()
+	scene(solarSystem)
+	solarSystem.notifications.reattach(solarSystem)
+	solarSystem.sceneName = name
+	return nil
+}
+
+// SceneName name of the scene being shown
+func (solarSystem *System) SceneName() string {
+	return solarSystem.sceneName
+}
+
+// orreryScene calm hands turning on every planet around a flickering sun, with a slow sweep across the wall
+func orreryScene(solarSystem *System) {
+	solarSystem.drawables.PushFront(&DrawLine{
+		startPosition:   r2.Point{X: 0, Y: 0},
+		endPosition:     r2.Point{X: 130, Y: 0},
+		traverseTime:    10.0,
+		currentPosition: r2.Point{X: 0, Y: 0},
+		lineDirection:   r2.Point{X: 1, Y: 0},
+		lineWidth:       6.0,
+		color:           color.RGBA{R: 0, G: 255, B: 255, A: 128},
+		zindex:          0,
+		fadeTime:        1.5,
+	})
+
+	// hands flash red for the first half radian of each turn
+	marker := NewGradientPalette(BlendRGB, Gradient{
+		{Position: 0, Color: RGBA{R: 255, G: 0, B: 0, A: 255}},
+		{Position: 0.5 / (2 * math.Pi), Color: RGBA{R: 255, G: 0, B: 0, A: 255}},
+		{Position: 0.5 / (2 * math.Pi), Color: RGBA{R: 255, G: 255, B: 255, A: 255}},
+		{Position: 1, Color: RGBA{R: 255, G: 255, B: 255, A: 255}},
+	})
+	solarSystem.AddDrawable(NewSun(solarSystem))
+	solarSystem.AddDrawable(NewMoon(Earth, solarSystem))
+	for planet := Mercury; planet <= Neptune; planet++ {
+		line := NewRotatingLine(planet, solarSystem)
+		line.SetPaint(NewPaint(marker, PaintByAngle, 2*math.Pi))
+		solarSystem.drawables.PushFront(line)
+	}
+
+	solarSystem.AddDrawable(NewCometSpawner(solarSystem, 120))
+}
+
+// showScene lively colored sweeps crossing the wall with fast hands on every planet
+func showScene(solarSystem *System) {
+	sweeps := []struct {
+		end       r2.Point
+		direction r2.Point
+		time      float64
+		color     color.RGBA
+	}{
+		{r2.Point{X: 130, Y: 0}, r2.Point{X: 1, Y: 0}, 3.0, color.RGBA{R: 255, G: 0, B: 255, A: 160}},
+		{r2.Point{X: 0, Y: 50}, r2.Point{X: 0, Y: 1}, 2.5, color.RGBA{R: 0, G: 255, B: 0, A: 160}},
+		{r2.Point{X: 130, Y: 50}, r2.Point{X: .707, Y: .707}, 4.0, color.RGBA{R: 255, G: 160, B: 0, A: 160}},
+	}
+	for i, sweep := range sweeps {
+		solarSystem.AddDrawable(&DrawLine{
+			startPosition:   r2.Point{X: 0, Y: 0},
+			endPosition:     sweep.end,
+			traverseTime:    sweep.time,
+			currentPosition: r2.Point{X: 0, Y: 0},
+			lineDirection:   sweep.direction,
+			lineWidth:       8.0,
+			color:           sweep.color,
+			zindex:          1,
+			mode:            LineMode(i % 2), // alternate wrapping and bouncing
+			fadeTime:        0.5,
+		})
+	}
+
+	for planet := Sun; planet <= Neptune; planet++ {
+		line := NewRotatingLine(planet, solarSystem)
+		line.traverseTime = 1.5
+		line.SetPaint(NewPaint(Palettes["rainbow"], PaintByAngle, 2*math.Pi))
+		solarSystem.AddDrawable(line)
+	}
+}
+
+// starfieldScene dim twinkling leds for overnight
+func starfieldScene(solarSystem *System) {
+	solarSystem.AddDrawable(NewTwinkle())
+	solarSystem.AddDrawable(NewMoon(Earth, solarSystem))
+	solarSystem.AddDrawable(NewCometSpawner(solarSystem, 60))
+}
+
+// cometsScene a steady stream of comets over the star field
+func cometsScene(solarSystem *System) {
+	solarSystem.AddDrawable(NewTwinkle())
+	solarSystem.AddDrawable(NewComet(solarSystem))
+	solarSystem.AddDrawable(NewCometSpawner(solarSystem, 6))
+}
+
+// sweepScene a line that eases across the wall, pauses, then eases back while shifting color
+func sweepScene(solarSystem *System) {
+	line := &DrawLine{
+		startPosition: r2.Point{X: 0, Y: 0},
+		endPosition:   r2.Point{X: WallWidth, Y: 0},
+		lineDirection: r2.Point{X: 1, Y: 0},
+		lineWidth:     10.0,
+		zindex:        1,
+	}
+
+	progress := NewTimeline(
+		Keyframe{Time: 0, Value: 0},
+		Keyframe{Time: 5, Value: 1, Easing: CubicInOut},
+		Keyframe{Time: 7, Value: 1},
+	).PingPong().Repeat(0)
+
+	hue := NewTimeline(
+		Keyframe{Time: 0, Color: RGBA{R: 0, G: 160, B: 255, A: 160}},
+		Keyframe{Time: 7, Color: RGBA{R: 255, G: 60, B: 160, A: 160}, Easing: SineInOut},
+	).PingPong().Repeat(0)
+
+	solarSystem.AddDrawable(Animated(line).Value(progress, line.SetProgress).Color(hue, line.SetColor))
+
+	for planet := Sun; planet <= Neptune; planet++ {
+		solarSystem.AddDrawable(NewRotatingLine(planet, solarSystem))
+	}
+}
+
+// solarWindScene particles streaming out from the Sun across the wall, with a light meteor shower
+func solarWindScene(solarSystem *System) {
+	sun := solarSystem.planets[Sun].position
+	outward := math.Atan2(WallHeight, WallWidth)
+
+	solarSystem.AddDrawable(NewParticles(sun).
+		Rate(12, 3).
+		Life(8, 3).
+		Velocity(outward, 0.8, 18, 6).
+		ColorOverLife(NewTimeline(
+			Keyframe{Time: 0, Color: RGBA{R: 255, G: 220, B: 80, A: 255}},
+			Keyframe{Time: 0.4, Color: RGBA{R: 255, G: 120, B: 20, A: 180}},
+			Keyframe{Time: 1, Color: RGBA{R: 180, G: 40, B: 0, A: 0}, Easing: EaseIn},
+		)).
+		SizeOverLife(NewTimeline(
+			Keyframe{Time: 0, Value: 3},
+			Keyframe{Time: 1, Value: 7, Easing: EaseOut},
+		)))
+
+	// a light meteor shower
+	solarSystem.AddDrawable(newMeteors(0.8))
+}
+
+// plasmaScene deep blues and purples drifting slowly across the whole wall
+func plasmaScene(solarSystem *System) {
+	solarSystem.AddDrawable(NewNoise(Palettes["plasma"]))
+}
+
+// planetsScene each body shows its own surface over a dim star field
+func planetsScene(solarSystem *System) {
+	solarSystem.AddDrawable(NewTwinkle())
+	solarSystem.AddDrawable(NewSun(solarSystem))
+	for planet := Sun; planet <= Neptune; planet++ {
+		if skin, ok := NewSkin(planet, solarSystem); ok {
+			solarSystem.AddDrawable(skin)
+		}
+	}
+}
+
+// colorScene the whole wall in the color set with SetFillColor
+func colorScene(solarSystem *System) {
+	solarSystem.AddDrawable(NewFill(solarSystem.fillColor))
+}
+
+// clockScene the Sun shows hours, Earth minutes and Mars seconds, over a dim drifting background with the gas giants turning
+func clockScene(solarSystem *System) {
+	background := NewNoise(Palettes["ocean"])
+	background.SetOpacity(0.3)
+	solarSystem.AddDrawable(background)
+
+	for _, planet := range []PlanetIndex{Jupiter, Saturn} {
+		if skin, ok := NewSkin(planet, solarSystem); ok {
+			skin.opacity = 140
+			solarSystem.AddDrawable(skin)
+		}
+	}
+
+	solarSystem.AddDrawable(NewClockHand(Sun, ClockHours, RGBA{R: 255, G: 180, B: 40, A: 255}, solarSystem))
+	solarSystem.AddDrawable(NewClockHand(Earth, ClockMinutes, RGBA{R: 200, G: 230, B: 255, A: 255}, solarSystem))
+	solarSystem.AddDrawable(NewClockHand(Mars, ClockSeconds, RGBA{R: 255, G: 40, B: 20, A: 255}, solarSystem))
+}
+
+// countdownScene the planets go dark one by one from Neptune as the countdown runs out
+func countdownScene(solarSystem *System) {
+	solarSystem.AddDrawable(NewCountdownDisplay(solarSystem, false))
+}
+
+// timerScene a ring drains around the Sun as the countdown runs out, over a dim star field
+func timerScene(solarSystem *System) {
+	solarSystem.AddDrawable(NewTwinkle())
+	solarSystem.AddDrawable(NewCountdownDisplay(solarSystem, true))
+}
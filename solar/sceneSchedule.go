@@ -0,0 +1,175 @@
+package solar
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// how often the schedule is checked for a new scene, in seconds
+const sceneCheckInterval = 1.0
+
+// SceneRule switches to a scene at a time of day, on the days it applies to
+// When several rules start at the same time the one listed last wins, so list holiday rules after everyday ones
+type SceneRule struct {
+	Scene string    `json:"scene"`
+	At    DailyTime `json:"at"`
+
+	// days of the week the rule applies, such as ["mon", "tue"], every day if empty
+	Weekdays []string `json:"weekdays,omitempty"`
+
+	// first and last day of the year the rule applies as MM-DD, such as "12-20" to "01-02", every day if empty
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+}
+
+// SceneSchedule changes the scene of the system through the day
+// A scene set by hand holds until the schedule next changes
+type SceneSchedule struct {
+	rules []SceneRule
+
+	// where sun events in the rules are computed
+	location *Location
+
+	// seconds since the schedule was last checked
+	sinceCheck float64
+
+	// when the scene was set by hand, zero if it follows the schedule
+	manualSince time.Time
+
+	// stop following the schedule altogether, such as while calibrating
+	held bool
+}
+
+// NewSceneSchedule an empty schedule that leaves the scene alone
+func NewSceneSchedule() *SceneSchedule {
+	return &SceneSchedule{sinceCheck: sceneCheckInterval}
+}
+
+// SetRules follow rules each day, sun events are computed for location
+func (schedule *SceneSchedule) SetRules(rules []SceneRule, location *Location) error {
+	for _, rule := range rules {
+		if _, ok := scenes[rule.Scene]; !ok {
+			return fmt.Errorf("unknown scene %q", rule.Scene)
+		}
+		if err := rule.At.Validate(); err != nil {
+			return err
+		}
+		for _, weekday := range rule.Weekdays {
+			if _, ok := parseWeekday(weekday); !ok {
+				return fmt.Errorf("scene %s: unknown weekday %q", rule.Scene, weekday)
+			}
+		}
+		if (rule.From == "") != (rule.To == "") {
+			return fmt.Errorf("scene %s: from and to must be used together", rule.Scene)
+		}
+		for _, day := range []string{rule.From, rule.To} {
+			if _, err := time.Parse("01-02", day); day != "" && err != nil {
+				return fmt.Errorf("scene %s: day %q must be MM-DD", rule.Scene, day)
+			}
+		}
+	}
+
+	schedule.rules = rules
+	schedule.location = location
+	schedule.sinceCheck = sceneCheckInterval
+	return nil
+}
+
+// SetManual note the scene was just set by hand
+func (schedule *SceneSchedule) SetManual(now time.Time) {
+	schedule.manualSince = now
+}
+
+// Hold stop changing the scene
+func (schedule *SceneSchedule) Hold() {
+	schedule.held = true
+}
+
+// Manual if the scene was set by hand and the schedule has not taken over again
+func (schedule *SceneSchedule) Manual() bool {
+	return !schedule.manualSince.IsZero()
+}
+
+// Animate switch solarSystem to the scheduled scene once it changes
+func (schedule *SceneSchedule) Animate(dt float64, now time.Time, solarSystem *System) {
+	schedule.sinceCheck += dt
+	if schedule.sinceCheck < sceneCheckInterval || len(schedule.rules) == 0 || schedule.held {
+		return
+	}
+	schedule.sinceCheck = 0
+
+	scene, since, ok := schedule.scheduledScene(now)
+	if !ok || !since.After(schedule.manualSince) {
+		return
+	}
+	schedule.manualSince = time.Time{}
+
+	if scene != solarSystem.SceneName() {
+		log.Print("Schedule changed scene to ", scene)
+		solarSystem.SetScene(scene)
+	}
+}
+
+// scheduledScene the scene of the most recent rule to start before now, and when it started
+func (schedule *SceneSchedule) scheduledScene(now time.Time) (scene string, since time.Time, ok bool) {
+	// rules from yesterday are still in effect until the first rule of today
+	for _, date := range []time.Time{now.AddDate(0, 0, -1), now} {
+		date = date.In(schedule.location.TimeLocation())
+		for _, rule := range schedule.rules {
+			if !rule.appliesOn(date) {
+				continue
+			}
+			at, happens, _ := rule.At.On(date, schedule.location)
+			if !happens || at.After(now) || at.Before(since) {
+				continue
+			}
+			scene, since, ok = rule.Scene, at, true
+		}
+	}
+	return scene, since, ok
+}
+
+// appliesOn if the weekdays and date range of the rule include the day of date
+func (rule *SceneRule) appliesOn(date time.Time) bool {
+	if len(rule.Weekdays) > 0 {
+		found := false
+		for _, name := range rule.Weekdays {
+			if weekday, _ := parseWeekday(name); weekday == date.Weekday() {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if rule.From != "" {
+		from, _ := time.Parse("01-02", rule.From)
+		to, _ := time.Parse("01-02", rule.To)
+		day := int(date.Month())*100 + date.Day()
+		first := int(from.Month())*100 + from.Day()
+		last := int(to.Month())*100 + to.Day()
+
+		// a range like 12-20 to 01-02 wraps over the new year
+		if first <= last {
+			return day >= first && day <= last
+		}
+		return day >= first || day <= last
+	}
+
+	return true
+}
+
+// parseWeekday accepts full or three letter day names in any case
+func parseWeekday(name string) (time.Weekday, bool) {
+	name = strings.ToLower(name)
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		full := strings.ToLower(weekday.String())
+		if name == full || name == full[:3] {
+			return weekday, true
+		}
+	}
+	return 0, false
+}
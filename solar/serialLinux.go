@@ -0,0 +1,55 @@
+// +build linux
+
+package solar
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// termios speed constants for the baud rates an Arduino can run at
+var serialBaudRates = map[int]uint32{
+	9600:    syscall.B9600,
+	19200:   syscall.B19200,
+	38400:   syscall.B38400,
+	57600:   syscall.B57600,
+	115200:  syscall.B115200,
+	230400:  syscall.B230400,
+	460800:  syscall.B460800,
+	500000:  syscall.B500000,
+	921600:  syscall.B921600,
+	1000000: syscall.B1000000,
+	2000000: syscall.B2000000,
+}
+
+// openSerialPort opens the tty at path in raw 8N1 mode at the given baud rate
+func openSerialPort(path string, baud int) (io.WriteCloser, error) {
+	rate, ok := serialBaudRates[baud]
+	if !ok {
+		return nil, fmt.Errorf("unsupported baud rate %d", baud)
+	}
+
+	file, err := os.OpenFile(path, syscall.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	termios := syscall.Termios{
+		Iflag:  syscall.IGNPAR,
+		Cflag:  syscall.CS8 | syscall.CREAD | syscall.CLOCAL | rate,
+		Ispeed: rate,
+		Ospeed: rate,
+	}
+	termios.Cc[syscall.VMIN] = 1
+
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, file.Fd(), uintptr(syscall.TCSETS), uintptr(unsafe.Pointer(&termios)))
+	if errno != 0 {
+		file.Close()
+		return nil, errno
+	}
+
+	return file, nil
+}
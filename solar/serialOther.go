@@ -0,0 +1,13 @@
+// +build !linux
+
+package solar
+
+import (
+	"errors"
+	"io"
+)
+
+// openSerialPort is only implemented for linux
+func openSerialPort(path string, baud int) (io.WriteCloser, error) {
+	return nil, errors.New("serial ports are not supported on this platform")
+}
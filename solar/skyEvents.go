@@ -0,0 +1,255 @@
+package solar
+
+import (
+	"image/color"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/golang/geo/r2"
+)
+
+// Kinds of SkyEvent
+const (
+	EventConjunction  = "conjunction"
+	EventOpposition   = "opposition"
+	EventSolstice     = "solstice"
+	EventEquinox      = "equinox"
+	EventMeteorShower = "meteorShower"
+)
+
+// seconds between replays of the animation of each event happening today
+const celebrationInterval = 45.0
+
+// SkyEvent something happening in the sky
+type SkyEvent struct {
+	Time time.Time `json:"time"`
+	Kind string    `json:"kind"`
+	Name string    `json:"name"`
+
+	// bodies involved, such as the two planets of a conjunction
+	Planets []string `json:"planets,omitempty"`
+}
+
+// meteorShowers peak nights of the major annual showers
+var meteorShowers = []struct {
+	name  string
+	month time.Month
+	day   int
+}{
+	{"Quadrantids", time.January, 3},
+	{"Lyrids", time.April, 22},
+	{"Eta Aquariids", time.May, 6},
+	{"Perseids", time.August, 12},
+	{"Orionids", time.October, 21},
+	{"Leonids", time.November, 17},
+	{"Geminids", time.December, 14},
+	{"Ursids", time.December, 22},
+}
+
+// seasons names of the points the Sun crosses every 90 degrees of longitude, starting at the March equinox
+var seasons = []struct {
+	kind string
+	name string
+}{
+	{EventEquinox, "March Equinox"},
+	{EventSolstice, "June Solstice"},
+	{EventEquinox, "September Equinox"},
+	{EventSolstice, "December Solstice"},
+}
+
+// SkyEvents every event from start until end in time order, meteor showers peak on their date in zone
+func SkyEvents(start time.Time, end time.Time, zone *time.Location) []SkyEvent {
+	var events []SkyEvent
+
+	planets := []PlanetIndex{Mercury, Venus, Mars, Jupiter, Saturn, Uranus, Neptune}
+	for i, first := range planets {
+		for _, second := range planets[i+1:] {
+			first, second := first, second
+			for _, at := range findCrossings(start, end, func(t time.Time) float64 {
+				return GeocentricLongitude(first, t) - GeocentricLongitude(second, t)
+			}) {
+				events = append(events, SkyEvent{at, EventConjunction, first.String() + " and " + second.String() + " in conjunction", []string{first.String(), second.String()}})
+			}
+		}
+	}
+
+	for _, planet := range []PlanetIndex{Mars, Jupiter, Saturn, Uranus, Neptune} {
+		planet := planet
+		for _, at := range findCrossings(start, end, func(t time.Time) float64 {
+			return GeocentricLongitude(planet, t) - GeocentricLongitude(Sun, t) - 180
+		}) {
+			events = append(events, SkyEvent{at, EventOpposition, planet.String() + " at opposition", []string{planet.String()}})
+		}
+	}
+
+	for quarter, season := range seasons {
+		longitude := float64(quarter) * 90
+		for _, at := range findCrossings(start, end, func(t time.Time) float64 {
+			return GeocentricLongitude(Sun, t) - longitude
+		}) {
+			events = append(events, SkyEvent{at, season.kind, season.name, []string{Sun.String()}})
+		}
+	}
+
+	for year := start.In(zone).Year(); year <= end.In(zone).Year(); year++ {
+		for _, shower := range meteorShowers {
+			at := time.Date(year, shower.month, shower.day, 0, 0, 0, 0, zone)
+			if !at.Before(start) && at.Before(end) {
+				events = append(events, SkyEvent{at, EventMeteorShower, shower.name + " meteor shower", nil})
+			}
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool { return events[i].Time.Before(events[j].Time) })
+	return events
+}
+
+// findCrossings times between start and end where angle in degrees passes through zero in either direction
+// Angles move at most a few degrees a day so they are checked daily, then each crossing is narrowed down to the minute
+func findCrossings(start time.Time, end time.Time, angle func(time.Time) float64) []time.Time {
+	wrapped := func(t time.Time) float64 {
+		return math.Remainder(angle(t), 360)
+	}
+
+	var crossings []time.Time
+	before, beforeAngle := start, wrapped(start)
+	for before.Before(end) {
+		after := before.Add(24 * time.Hour)
+		afterAngle := wrapped(after)
+
+		// a jump between +180 and -180 is the angle wrapping, not a crossing
+		if (beforeAngle < 0) != (afterAngle < 0) && math.Abs(afterAngle-beforeAngle) < 90 {
+			low, high := before, after
+			for high.Sub(low) > time.Minute {
+				middle := low.Add(high.Sub(low) / 2)
+				if (wrapped(middle) < 0) == (beforeAngle < 0) {
+					low = middle
+				} else {
+					high = middle
+				}
+			}
+			if !high.Before(start) && high.Before(end) {
+				crossings = append(crossings, high)
+			}
+		}
+
+		before, beforeAngle = after, afterAngle
+	}
+	return crossings
+}
+
+// SkyCalendar plays an animation every so often on the day of each sky event
+type SkyCalendar struct {
+
+	// local date the events of today were found for
+	day string
+
+	// events happening today
+	today []SkyEvent
+
+	// seconds until the animations of today play again
+	untilCelebration float64
+}
+
+// NewSkyCalendar a calendar that starts by finding the events of today
+func NewSkyCalendar() *SkyCalendar {
+	return &SkyCalendar{}
+}
+
+// Upcoming events from now until days later, with days in zone
+func (calendar *SkyCalendar) Upcoming(now time.Time, days int, zone *time.Location) []SkyEvent {
+	return SkyEvents(now, now.AddDate(0, 0, days), zone)
+}
+
+// Today events on the local day of the last Animate
+func (calendar *SkyCalendar) Today() []SkyEvent {
+	return calendar.today
+}
+
+// Animate play the animations of today's events on solarSystem, now is the simulation time
+func (calendar *SkyCalendar) Animate(dt float64, now time.Time, solarSystem *System) {
+	zone := solarSystem.location.TimeLocation()
+	local := now.In(zone)
+	if day := local.Format("2006-01-02"); day != calendar.day {
+		start := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, zone)
+		calendar.day = day
+		calendar.today = SkyEvents(start, start.AddDate(0, 0, 1), zone)
+		calendar.untilCelebration = 0
+	}
+
+	calendar.untilCelebration -= dt
+	if calendar.untilCelebration > 0 {
+		return
+	}
+	calendar.untilCelebration = celebrationInterval
+
+	// stay dark when the wall is off, and out of the way while calibrating
+	if solarSystem.SceneName() == "off" || solarSystem.scenes.held {
+		return
+	}
+	for _, event := range calendar.today {
+		celebrate(event, solarSystem)
+	}
+}
+
+// celebrate add the animation of event to solarSystem, each removes itself when done
+func celebrate(event SkyEvent, solarSystem *System) {
+	switch event.Kind {
+	case EventConjunction:
+		first, _ := ParsePlanetIndex(event.Planets[0])
+		second, _ := ParsePlanetIndex(event.Planets[1])
+		from := solarSystem.planets[first].position
+		to := solarSystem.planets[second].position
+
+		// a gold line joins the two planets, fading in and out
+		link := NewRotatingLine(first, solarSystem)
+		link.traverseTime = 0
+		link.length = to.Sub(from).Norm()
+		link.lineWidth = 2.5
+		link.zindex = 4
+		link.SetAngle(math.Atan2(to.Y-from.Y, to.X-from.X))
+		glow := NewTimeline(
+			Keyframe{Time: 0, Color: RGBA{R: 255, G: 200, B: 60, A: 0}},
+			Keyframe{Time: 2, Color: RGBA{R: 255, G: 200, B: 60, A: 255}, Easing: EaseOut},
+			Keyframe{Time: 8, Color: RGBA{R: 255, G: 200, B: 60, A: 255}},
+			Keyframe{Time: 10, Color: RGBA{R: 255, G: 200, B: 60, A: 0}, Easing: EaseIn},
+		)
+		solarSystem.AddDrawable(Animated(link).Color(glow, link.SetColor).RemoveWhenDone())
+
+	case EventOpposition:
+		planet, _ := ParsePlanetIndex(event.Planets[0])
+		position := solarSystem.planets[planet].position
+		solarSystem.AddDrawable(NewRipple(position, 20, 50, 4, color.RGBA{R: 255, G: 220, B: 120, A: 200}))
+		solarSystem.AddDrawable(NewRipple(position, 12, 30, 3, color.RGBA{R: 255, G: 220, B: 120, A: 200}))
+
+	case EventSolstice, EventEquinox:
+		ripple := color.RGBA{R: 120, G: 255, B: 140, A: 180}
+		if event.Name == "June Solstice" {
+			ripple = color.RGBA{R: 255, G: 170, B: 40, A: 180}
+		} else if event.Name == "December Solstice" {
+			ripple = color.RGBA{R: 140, G: 200, B: 255, A: 180}
+		}
+		solarSystem.AddDrawable(NewRipple(solarSystem.planets[Sun].position, 30, math.Hypot(WallWidth, WallHeight), 6, ripple))
+
+	case EventMeteorShower:
+		solarSystem.AddDrawable(newMeteors(8).For(8))
+	}
+}
+
+// newMeteors meteors entering above the wall at perSecond and falling steeply across it
+func newMeteors(perSecond float64) *DrawParticles {
+	return NewParticles(r2.Point{X: WallWidth / 2, Y: -10}).
+		Rate(perSecond, WallWidth/2).
+		Life(3, 0.5).
+		Velocity(math.Pi*0.6, 0.1, 50, 10).
+		Gravity(r2.Point{X: WallWidth / 2, Y: WallHeight * 4}, 10).
+		ColorOverLife(NewTimeline(
+			Keyframe{Time: 0, Color: RGBA{R: 255, G: 255, B: 255, A: 255}},
+			Keyframe{Time: 1, Color: RGBA{R: 120, G: 160, B: 255, A: 0}},
+		)).
+		SizeOverLife(NewTimeline(
+			Keyframe{Time: 0, Value: 4},
+			Keyframe{Time: 1, Value: 2},
+		))
+}
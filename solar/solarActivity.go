@@ -0,0 +1,114 @@
+package solar
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sunspot number treated as normal activity, around the middle of a solar cycle
+const normalSunspots = 100.0
+
+// SunLayout how lively the Sun effect is
+type SunLayout struct {
+
+	// strength of the corona flicker and how often flares erupt, 1 is normal and 0 is a calm sun
+	Intensity float64 `json:"intensity"`
+
+	// optional file of daily sunspot numbers that scales the intensity day by day
+	ActivityFile string `json:"activityFile,omitempty"`
+}
+
+// DefaultSunLayout a normally active sun with no activity file
+var DefaultSunLayout = SunLayout{Intensity: 1}
+
+// SolarActivity daily sunspot numbers read from a local file
+// Lines are either date,number such as 2024-05-10,180 or the SILSO daily format year;month;day;fraction;number;...
+// Blank lines and lines starting with # are ignored, as are days with a negative number
+type SolarActivity struct {
+	days []activityDay
+}
+
+// activityDay sunspot number on one day
+type activityDay struct {
+	date     time.Time
+	sunspots float64
+}
+
+// LoadSolarActivity read daily sunspot numbers from path
+func LoadSolarActivity(path string) (*SolarActivity, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	activity := &SolarActivity{}
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		day, err := parseActivityDay(text)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", path, line, err)
+		}
+		if day.sunspots >= 0 {
+			activity.days = append(activity.days, day)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(activity.days, func(i, j int) bool { return activity.days[i].date.Before(activity.days[j].date) })
+	return activity, nil
+}
+
+// parseActivityDay one line of either supported format
+func parseActivityDay(text string) (activityDay, error) {
+	fields := strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == ';' })
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+	}
+
+	if len(fields) == 2 {
+		date, err := time.Parse("2006-01-02", fields[0])
+		if err != nil {
+			return activityDay{}, err
+		}
+		sunspots, err := strconv.ParseFloat(fields[1], 64)
+		return activityDay{date, sunspots}, err
+	}
+
+	if len(fields) >= 5 {
+		var parts [3]int
+		for i := range parts {
+			value, err := strconv.Atoi(fields[i])
+			if err != nil {
+				return activityDay{}, err
+			}
+			parts[i] = value
+		}
+		sunspots, err := strconv.ParseFloat(fields[4], 64)
+		return activityDay{time.Date(parts[0], time.Month(parts[1]), parts[2], 0, 0, 0, 0, time.UTC), sunspots}, err
+	}
+
+	return activityDay{}, fmt.Errorf("expected date,sunspots or year;month;day;fraction;sunspots")
+}
+
+// Scale activity on the day of date relative to normal, from the latest day on or before it, false if the file has no earlier days
+func (activity *SolarActivity) Scale(date time.Time) (float64, bool) {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	next := sort.Search(len(activity.days), func(i int) bool { return activity.days[i].date.After(day) })
+	if next == 0 {
+		return 0, false
+	}
+	return activity.days[next-1].sunspots / normalSunspots, true
+}
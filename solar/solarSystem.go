@@ -3,7 +3,9 @@ package solar
 import (
 	"container/list"
 	"math"
-	"image/color"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/golang/geo/r2"
 )
@@ -34,6 +36,22 @@ func (planet PlanetIndex) String() string {
 	return names[planet]
 }
 
+// Size of the wall in the units used for positions
+const (
+	WallWidth  = 136
+	WallHeight = 91
+)
+
+// ParsePlanetIndex return the PlanetIndex with the given name, case insensitive
+func ParsePlanetIndex(name string) (PlanetIndex, bool) {
+	for planet := Sun; planet <= Neptune; planet++ {
+		if strings.EqualFold(planet.String(), name) {
+			return planet, true
+		}
+	}
+	return 0, false
+}
+
 // Planet information about a planet
 type Planet struct {
 
@@ -56,11 +74,59 @@ type Planet struct {
 // System all objects that exist in the system, the state of the world
 type System struct {
 
+	// held while animating and rendering, and by web handlers that use the system
+	sync.Mutex
+
 	// The planets
 	planets [PlanetCount]Planet
 
 	// All of the drawable items, stored in increasing ZIndex order
 	drawables *list.List
+
+	// where each led is physically wired
+	stripMap *StripMap
+
+	// how each output channel is driven
+	channels []ChannelLayout
+
+	// master brightness applied to every led
+	brightness *Brightness
+
+	// where the wall is, for anything that follows the sun
+	location *Location
+
+	// picks the scene by time of day
+	scenes *SceneSchedule
+
+	// name of the scene being shown
+	sceneName string
+
+	// colors in strip order that replace the drawables when not nil, such as from a LightingInput
+	ledOverride []RGBA
+
+	// how lively the Sun effect is
+	sun SunLayout
+
+	// daily sunspot numbers scaling the sun intensity, nil if there is no activity file
+	solarActivity *SolarActivity
+
+	// time astronomical effects are drawn for
+	clock *SimulationClock
+
+	// plays animations on the days of sky events
+	sky *SkyCalendar
+
+	// how the clock scene tells time
+	clockFace ClockLayout
+
+	// timer shown by the countdown scenes
+	countdown *Countdown
+
+	// overlays shown on top of every scene
+	notifications *NotificationQueue
+
+	// color of the color scene
+	fillColor RGBA
 }
 
 // DefaultSystem create a solar system with all the data for the planets initialized
@@ -77,61 +143,25 @@ func DefaultSystem() *System {
 	system.planets[Uranus] = Planet{r2.Point{X: 106, Y: 45}, 6, 27, -2.78, -1.0}
 	system.planets[Neptune] = Planet{r2.Point{X: 126, Y: 25}, 4, 27, -1.00, 1.0}
 
+	system.stripMap, _ = NewStripMap(system, nil)
+	system.channels = []ChannelLayout{
+		{Pin: 18, Brightness: 255, StripType: "grb"},
+		{Pin: 13, Brightness: 255, StripType: "grb"},
+	}
+
+	system.brightness = NewBrightness()
+	system.sun = DefaultSunLayout
+	system.clock = NewSimulationClock(time.Now())
+	system.sky = NewSkyCalendar()
+	system.clockFace = DefaultClockLayout
+	system.countdown = NewCountdown()
+	system.notifications = NewNotificationQueue()
+	system.fillColor = RGBA{R: 255, G: 180, B: 100, A: 255}
+
 	system.drawables = list.New()
 
-        system.drawables.PushFront(&DrawLine{
-                startPosition:   r2.Point{X: 0, Y: 0},
-                endPosition:     r2.Point{X: 130, Y: 0},
-                traverseTime:    10.0,
-                currentPosition: r2.Point{X: 0, Y: 0},
-                lineDirection:   r2.Point{X: 1, Y: 0},
-                lineWidth:       6.0,
-                color:           color.RGBA{R: 0, G: 255, B: 255, A: 128},
-                zindex:          0,
-         })
-
-	system.drawables.PushFront(NewRotatingLine(Sun, system))
-	system.drawables.PushFront(NewRotatingLine(Mercury, system))
-	system.drawables.PushFront(NewRotatingLine(Venus, system))
-	system.drawables.PushFront(NewRotatingLine(Earth, system))
-	system.drawables.PushFront(NewRotatingLine(Mars, system))
-	system.drawables.PushFront(NewRotatingLine(Jupiter, system))
-	system.drawables.PushFront(NewRotatingLine(Saturn, system))
-	system.drawables.PushFront(NewRotatingLine(Uranus, system))
-	system.drawables.PushFront(NewRotatingLine(Neptune, system))
-
-//	 system.drawables.PushFront(&DrawLine{
-//	 	startPosition:   r2.Point{X: 0, Y: 0},
-//	 	endPosition:     r2.Point{X: 130, Y: 50},
-//	 	traverseTime:    15.0,
-//	 	currentPosition: r2.Point{X: 0, Y: 0},
-//	 	lineDirection:   r2.Point{X: .707, Y: .707},
-//	 	lineWidth:       24.0,
-//	 	color:           color.RGBA{R: 255, G: 255, B: 0, A: 128},
-//	 	zindex:          3,
-//	 })
-
-//	system.drawables.PushFront(&DrawLine{
-//	 	startPosition:   r2.Point{X: 0, Y: 0},
-//	 	endPosition:     r2.Point{X: 130, Y: 0},
-//	 	traverseTime:    10.0,
-//	 	currentPosition: r2.Point{X: 0, Y: 0},
-//	 	lineDirection:   r2.Point{X: 1, Y: 0},
-//	 	lineWidth:       6.0,
-//	 	color:           color.RGBA{R: 255, G: 0, B: 255, A: 128},
-//	 	zindex:          0,
-//	 })
-
-//	 system.drawables.PushFront(&DrawLine{
-//	 	startPosition:   r2.Point{X: 0, Y: 0},
-//	 	endPosition:     r2.Point{X: 0, Y: 50},
-//	 	traverseTime:    12.0,
-//	 	currentPosition: r2.Point{X: 0, Y: 0},
-//	 	lineDirection:   r2.Point{X: 0, Y: 1},
-//	 	lineWidth:       12.0,
-//	 	color:           color.RGBA{R: 0, G: 255, B: 0, A: 128},
-//	 	zindex:          4,
-//	})
+	system.scenes = NewSceneSchedule()
+	system.SetScene("orrery")
 
 	return system
 }
@@ -158,9 +188,158 @@ func (solarSystem *System) LedPosition(planetI PlanetIndex, ledIndex int) r2.Poi
 	return ledOffset.Add(planet.position)
 }
 
+// StripMap return where each led is physically wired
+func (solarSystem *System) StripMap() *StripMap {
+	return solarSystem.stripMap
+}
+
+// Channels return how each output channel is driven
+func (solarSystem *System) Channels() []ChannelLayout {
+	return solarSystem.channels
+}
+
+// RenderColors computes the color of every led in strip order, Sun to Neptune, into colors
+func (solarSystem *System) RenderColors(colors []RGBA) {
+	if solarSystem.ledOverride != nil {
+		copy(colors, solarSystem.ledOverride)
+	} else {
+		solarSystem.renderDrawables(colors)
+	}
+
+	// scale every led by the master brightness
+	level := uint32(solarSystem.brightness.Level() * 256)
+	if level >= 256 {
+		return
+	}
+	for ledIndex, color := range colors {
+		colors[ledIndex] = RGBA{
+			R: uint8((uint32(color.R) * level) >> 8),
+			G: uint8((uint32(color.G) * level) >> 8),
+			B: uint8((uint32(color.B) * level) >> 8),
+			A: color.A,
+		}
+	}
+}
+
+// renderDrawables blend every drawable into the color of each led
+func (solarSystem *System) renderDrawables(colors []RGBA) {
+	firstLedOffset := 0
+
+	// loop through every planet
+	for planetIndex := 0; planetIndex < PlanetCount; planetIndex++ {
+		planet := solarSystem.planets[planetIndex]
+
+		for led := 0; led < planet.ledCount; led++ {
+			colors[led+firstLedOffset] = RGBA{R: 0, G: 0, B: 0, A: 255}
+		}
+
+		// loop through every drawable object
+		for curElement := solarSystem.drawables.Front(); curElement != nil; curElement = curElement.Next() {
+			drawable := curElement.Value.(Drawable)
+
+			// bounding circle check to see if this should affect this planet
+			if !drawable.Affects(planet.position, planet.radius) {
+				continue
+			}
+
+			// loop through every led on this planet
+			for led := 0; led < planet.ledCount; led++ {
+				ledIndex := led + firstLedOffset
+				ledPosition := solarSystem.LedPosition(PlanetIndex(planetIndex), led)
+				colors[ledIndex] = drawable.ColorAt(ledPosition, colors[ledIndex])
+			}
+		}
+
+		firstLedOffset += planet.ledCount
+	}
+}
+
+// AddDrawable insert drawable so the list stays in increasing ZIndex order, after any with the same ZIndex
+func (solarSystem *System) AddDrawable(drawable Drawable) {
+	for curElement := solarSystem.drawables.Back(); curElement != nil; curElement = curElement.Prev() {
+		if curElement.Value.(Drawable).ZIndex() <= drawable.ZIndex() {
+			solarSystem.drawables.InsertAfter(drawable, curElement)
+			return
+		}
+	}
+	solarSystem.drawables.PushFront(drawable)
+}
+
+// ClearDrawables remove every drawable
+func (solarSystem *System) ClearDrawables() {
+	solarSystem.drawables.Init()
+}
+
+// SetLedOverride replaces the output of the drawables with colors, in strip order, until called with nil
+func (solarSystem *System) SetLedOverride(colors []RGBA) {
+	solarSystem.ledOverride = colors
+}
+
+// SceneSchedule return the schedule that picks the scene
+func (solarSystem *System) SceneSchedule() *SceneSchedule {
+	return solarSystem.scenes
+}
+
+// Brightness return the master brightness
+func (solarSystem *System) Brightness() *Brightness {
+	return solarSystem.brightness
+}
+
+// Clock return the time astronomical effects are drawn for
+func (solarSystem *System) Clock() *SimulationClock {
+	return solarSystem.clock
+}
+
+// SkyCalendar return the calendar of sky events
+func (solarSystem *System) SkyCalendar() *SkyCalendar {
+	return solarSystem.sky
+}
+
+// Countdown return the timer shown by the countdown scenes
+func (solarSystem *System) Countdown() *Countdown {
+	return solarSystem.countdown
+}
+
+// Notifications return the overlays shown on top of every scene
+func (solarSystem *System) Notifications() *NotificationQueue {
+	return solarSystem.notifications
+}
+
+// FillColor color of the color scene
+func (solarSystem *System) FillColor() RGBA {
+	return solarSystem.fillColor
+}
+
+// SetFillColor change the color of the color scene, updating it if it is showing
+func (solarSystem *System) SetFillColor(color RGBA) {
+	color.A = 255
+	solarSystem.fillColor = color
+	if solarSystem.sceneName == "color" {
+		solarSystem.SetScene("color")
+	}
+}
+
+// SunIntensity how lively the Sun effect is at now, scaled by the solar activity file when there is one
+func (solarSystem *System) SunIntensity(now time.Time) float64 {
+	if solarSystem.solarActivity != nil {
+		if scale, ok := solarSystem.solarActivity.Scale(now); ok {
+			return solarSystem.sun.Intensity * scale
+		}
+	}
+	return solarSystem.sun.Intensity
+}
+
 // Animate moves all drawables forward in time
 func (solarSystem *System) Animate(dt float64) {
 
+	solarSystem.clock.Animate(dt)
+
+	now := time.Now()
+	solarSystem.brightness.Animate(dt, now)
+	solarSystem.scenes.Animate(dt, now, solarSystem)
+	solarSystem.sky.Animate(dt, solarSystem.clock.Now(), solarSystem)
+	solarSystem.notifications.Animate(now, solarSystem)
+
 	for curElement := solarSystem.drawables.Front(); curElement != nil; {
 
 		drawable := curElement.Value.(Drawable)
@@ -0,0 +1,24 @@
+package solar
+
+// StripChannel what a StripDriver needs to set up one output channel
+type StripChannel struct {
+	Pin        int
+	LedCount   int
+	Brightness int
+}
+
+// StripDriver sends packed colors out to physical led strips, one strip per channel
+type StripDriver interface {
+
+	// Init prepare an output for each channel
+	Init(channels []StripChannel) error
+
+	// SetLed set the packed 24 bit color of led index on channel, sent on the next Render
+	SetLed(channel int, index int, value uint32)
+
+	// Render send the colors of every channel to the strips
+	Render() error
+
+	// Fini turn off every led and release the outputs
+	Fini()
+}
@@ -0,0 +1,128 @@
+package solar
+
+import (
+	"fmt"
+)
+
+// StripSegment one planet's run of leds on a physical strip, segments are listed in the order they are chained
+type StripSegment struct {
+	Planet string `json:"planet"`
+
+	// output channel the segment is wired to
+	Channel int `json:"channel"`
+
+	// unused leds before this planet on the channel, such as jumpers between bodies
+	Skip int `json:"skip,omitempty"`
+
+	// strip data runs from the planet's last led to led 0
+	Reversed bool `json:"reversed,omitempty"`
+}
+
+// StripMap maps the logical strip order, every planet's leds from Sun to Neptune, to where each led is physically wired
+type StripMap struct {
+
+	// segments the map was built from, kept so they can be saved back to the layout
+	segments []StripSegment
+
+	// output channel of each logical led
+	channel []int
+
+	// index on its channel of each logical led
+	index []int
+
+	// number of physical leds on each channel, including skipped ones
+	channelLength []int
+}
+
+// NewStripMap build the mapping for solarSystem from segments in chained order
+// With no segments every planet is chained on channel 0 in PlanetIndex order
+func NewStripMap(solarSystem *System, segments []StripSegment) (*StripMap, error) {
+	if len(segments) == 0 {
+		for planet := Sun; planet <= Neptune; planet++ {
+			segments = append(segments, StripSegment{Planet: planet.String()})
+		}
+	}
+
+	ledCount := solarSystem.LedCount()
+	stripMap := &StripMap{
+		segments: segments,
+		channel:  make([]int, ledCount),
+		index:    make([]int, ledCount),
+	}
+
+	// index of the first led of each planet in logical order
+	var planetOffset [PlanetCount]int
+	offset := 0
+	for planetIndex := 0; planetIndex < PlanetCount; planetIndex++ {
+		planetOffset[planetIndex] = offset
+		offset += solarSystem.planets[planetIndex].ledCount
+	}
+
+	var mapped [PlanetCount]bool
+	for _, segment := range segments {
+		planetIndex, ok := ParsePlanetIndex(segment.Planet)
+		if !ok {
+			return nil, fmt.Errorf("strip segment for unknown planet %q", segment.Planet)
+		}
+		if mapped[planetIndex] {
+			return nil, fmt.Errorf("%v is in the strip more than once", planetIndex)
+		}
+		if segment.Channel < 0 || segment.Skip < 0 {
+			return nil, fmt.Errorf("strip segment for %v has a negative channel or skip", planetIndex)
+		}
+		mapped[planetIndex] = true
+
+		for len(stripMap.channelLength) <= segment.Channel {
+			stripMap.channelLength = append(stripMap.channelLength, 0)
+		}
+
+		first := stripMap.channelLength[segment.Channel] + segment.Skip
+		ledCount := solarSystem.planets[planetIndex].ledCount
+		for led := 0; led < ledCount; led++ {
+			physical := first + led
+			if segment.Reversed {
+				physical = first + ledCount - 1 - led
+			}
+
+			stripMap.channel[planetOffset[planetIndex]+led] = segment.Channel
+			stripMap.index[planetOffset[planetIndex]+led] = physical
+		}
+		stripMap.channelLength[segment.Channel] = first + ledCount
+	}
+
+	for planetIndex := 0; planetIndex < PlanetCount; planetIndex++ {
+		if !mapped[planetIndex] {
+			return nil, fmt.Errorf("%v is missing from the strip", PlanetIndex(planetIndex))
+		}
+	}
+
+	return stripMap, nil
+}
+
+// ChannelCount number of output channels used
+func (stripMap *StripMap) ChannelCount() int {
+	return len(stripMap.channelLength)
+}
+
+// ChannelLength number of physical leds on channel, including skipped ones
+func (stripMap *StripMap) ChannelLength(channel int) int {
+	return stripMap.channelLength[channel]
+}
+
+// Physical returns where the led at logical index is wired
+func (stripMap *StripMap) Physical(logical int) (channel int, index int) {
+	return stripMap.channel[logical], stripMap.index[logical]
+}
+
+// ChannelColors fill physical with the colors of the leds wired to channel, skipped leds are left off
+func (stripMap *StripMap) ChannelColors(channel int, logical []RGBA, physical []RGBA) {
+	for i := range physical {
+		physical[i] = RGBA{R: 0, G: 0, B: 0, A: 255}
+	}
+
+	for ledIndex, color := range logical {
+		if stripMap.channel[ledIndex] == channel {
+			physical[stripMap.index[ledIndex]] = color
+		}
+	}
+}
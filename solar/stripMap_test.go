@@ -0,0 +1,79 @@
+package solar
+
+import (
+	"testing"
+)
+
+func TestStripMapTwoChannels(t *testing.T) {
+	solarSystem := DefaultSystem()
+
+	// inner planets chained on channel 0, outer planets on channel 1 after two jumper leds with Saturn wired backwards
+	stripMap, err := NewStripMap(solarSystem, []StripSegment{
+		{Planet: "Sun", Channel: 0},
+		{Planet: "Mercury", Channel: 0},
+		{Planet: "Venus", Channel: 0},
+		{Planet: "Earth", Channel: 0},
+		{Planet: "Mars", Channel: 0},
+		{Planet: "Jupiter", Channel: 1, Skip: 2},
+		{Planet: "Saturn", Channel: 1, Reversed: true},
+		{Planet: "Uranus", Channel: 1},
+		{Planet: "Neptune", Channel: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	solarSystem.stripMap = stripMap
+	solarSystem.channels = []ChannelLayout{
+		{Pin: 18, Brightness: 255, StripType: "grb"},
+		{Pin: 13, Brightness: 255, StripType: "rgb"},
+	}
+
+	driver := renderFake(t, solarSystem)
+	if len(driver.Channels) != 2 || driver.Channels[0].Pin != 18 || driver.Channels[1].Pin != 13 {
+		t.Fatalf("channels %+v", driver.Channels)
+	}
+
+	// Sun to Mars is 27 + 4*17 leds, Jupiter to Neptune 2 skipped + 4*27
+	if driver.Channels[0].LedCount != 95 || driver.Channels[1].LedCount != 110 {
+		t.Fatalf("channel lengths %d and %d, want 95 and 110", driver.Channels[0].LedCount, driver.Channels[1].LedCount)
+	}
+
+	tests := []struct {
+		logical, channel, index int
+	}{
+		{0, 0, 0},     // first led of the Sun
+		{94, 0, 94},   // last led of Mars
+		{95, 1, 2},    // first led of Jupiter, after the jumpers
+		{121, 1, 28},  // last led of Jupiter
+		{122, 1, 55},  // first led of Saturn is the end of its reversed run
+		{148, 1, 29},  // last led of Saturn
+		{149, 1, 56},  // first led of Uranus
+		{202, 1, 109}, // last led of Neptune
+	}
+	for _, test := range tests {
+		// light only this led, red so the packing of each channel's strip type shows
+		colors := make([]RGBA, solarSystem.LedCount())
+		colors[test.logical] = RGBA{R: 255, A: 255}
+		solarSystem.SetLedOverride(colors)
+
+		display, err := NewLedDisplay(solarSystem, driver)
+		if err != nil {
+			t.Fatal(err)
+		}
+		display.Render(solarSystem)
+		frame := driver.LastFrame()
+
+		want := packColor(solarSystem.channels[test.channel].StripType, 126, 0, 0)
+		for channel := range frame {
+			for index, value := range frame[channel] {
+				lit := channel == test.channel && index == test.index
+				if lit && value != want {
+					t.Errorf("led %d on channel %d index %d is %06x, want %06x", test.logical, channel, index, value, want)
+				}
+				if !lit && value != 0 {
+					t.Errorf("led %d lit channel %d index %d", test.logical, channel, index)
+				}
+			}
+		}
+	}
+}
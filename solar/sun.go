@@ -0,0 +1,85 @@
+package solar
+
+import (
+	"math"
+	"time"
+)
+
+// Sun altitudes in degrees that define the events of a day
+const (
+	// center of the sun is below the horizon by refraction plus its radius
+	SunriseAltitude = -0.833
+
+	// civil twilight, still light enough to see outside
+	CivilTwilightAltitude = -6.0
+
+	// nautical twilight, the horizon is no longer visible
+	NauticalTwilightAltitude = -12.0
+)
+
+// Location where the wall is installed, north and east are positive degrees
+type Location struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+
+	// IANA time zone such as America/Chicago, defaults to the local time zone of the machine
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// loaded from TimeZone on first use
+	timeLocation *time.Location
+}
+
+// TimeLocation the time zone of the location
+func (location *Location) TimeLocation() *time.Location {
+	if location == nil || location.TimeZone == "" {
+		return time.Local
+	}
+	if location.timeLocation == nil {
+		timeLocation, err := time.LoadLocation(location.TimeZone)
+		if err != nil {
+			timeLocation = time.Local
+		}
+		location.timeLocation = timeLocation
+	}
+	return location.timeLocation
+}
+
+// SunEvent returns when the sun crosses altitude degrees on the day of date at location, rising in the morning and setting in the evening
+// ok is false if the sun stays above or below altitude all day, such as near the poles
+func SunEvent(date time.Time, location *Location, altitude float64) (rising time.Time, setting time.Time, ok bool) {
+	const degrees = math.Pi / 180
+
+	// julian day of noon at this location, counted from the J2000 epoch
+	year, month, day := date.In(location.TimeLocation()).Date()
+	noon := time.Date(year, month, day, 12, 0, 0, 0, location.TimeLocation())
+	n := math.Round(julianDate(noon) - 2451545.0 + 0.0008)
+
+	meanSolarTime := n - location.Longitude/360
+	meanAnomaly := math.Mod(357.5291+0.98560028*meanSolarTime, 360)
+	center := 1.9148*math.Sin(meanAnomaly*degrees) + 0.0200*math.Sin(2*meanAnomaly*degrees) + 0.0003*math.Sin(3*meanAnomaly*degrees)
+	eclipticLongitude := math.Mod(meanAnomaly+center+180+102.9372, 360)
+	transit := 2451545.0 + meanSolarTime + 0.0053*math.Sin(meanAnomaly*degrees) - 0.0069*math.Sin(2*eclipticLongitude*degrees)
+
+	declination := math.Asin(math.Sin(eclipticLongitude*degrees) * math.Sin(23.44*degrees))
+	latitude := location.Latitude * degrees
+	cosHourAngle := (math.Sin(altitude*degrees) - math.Sin(latitude)*math.Sin(declination)) / (math.Cos(latitude) * math.Cos(declination))
+	if cosHourAngle < -1 || cosHourAngle > 1 {
+		return time.Time{}, time.Time{}, false
+	}
+	hourAngle := math.Acos(cosHourAngle) / degrees
+
+	rising = fromJulianDate(transit - hourAngle/360).In(location.TimeLocation())
+	setting = fromJulianDate(transit + hourAngle/360).In(location.TimeLocation())
+	return rising, setting, true
+}
+
+// julianDate days since noon January 1, 4713 BC
+func julianDate(t time.Time) float64 {
+	return float64(t.Unix())/86400 + 2440587.5
+}
+
+// fromJulianDate the time of julian date
+func fromJulianDate(julian float64) time.Time {
+	seconds := (julian - 2440587.5) * 86400
+	return time.Unix(0, int64(seconds*1e9)).UTC()
+}
@@ -0,0 +1,120 @@
+package solar
+
+import (
+	"math"
+)
+
+// Keyframe value of a property at a point in a Timeline
+// Value is used for numbers and Color for colors, a timeline usually only sets one of them
+type Keyframe struct {
+
+	// seconds from the start of the timeline
+	Time float64
+
+	Value float64
+	Color RGBA
+
+	// how the property moves from the previous keyframe to this one, nil is Linear
+	Easing Easing
+}
+
+// Timeline interpolates between keyframes, optionally repeating and playing backwards on every other pass
+type Timeline struct {
+
+	// keyframes in increasing Time order
+	keyframes []Keyframe
+
+	// every other pass plays backwards so the property returns instead of jumping to the start
+	pingPong bool
+
+	// number of passes to play, where a ping pong there and back is two passes, 0 plays forever
+	repeat int
+}
+
+// NewTimeline create a timeline playing keyframes once, they must be in increasing Time order
+func NewTimeline(keyframes ...Keyframe) *Timeline {
+	return &Timeline{keyframes: keyframes, repeat: 1}
+}
+
+// PingPong play every other pass backwards
+func (timeline *Timeline) PingPong() *Timeline {
+	timeline.pingPong = true
+	return timeline
+}
+
+// Repeat play passes times, 0 for forever
+func (timeline *Timeline) Repeat(passes int) *Timeline {
+	timeline.repeat = passes
+	return timeline
+}
+
+// Duration seconds of a single pass
+func (timeline *Timeline) Duration() float64 {
+	if len(timeline.keyframes) == 0 {
+		return 0
+	}
+	return timeline.keyframes[len(timeline.keyframes)-1].Time
+}
+
+// Finished if every pass has played by elapsed seconds
+func (timeline *Timeline) Finished(elapsed float64) bool {
+	return timeline.repeat > 0 && elapsed >= timeline.Duration()*float64(timeline.repeat)
+}
+
+// Value number at elapsed seconds into the timeline
+func (timeline *Timeline) Value(elapsed float64) float64 {
+	from, to, progress := timeline.locate(elapsed)
+	return from.Value + (to.Value-from.Value)*progress
+}
+
+// Color color at elapsed seconds into the timeline
+func (timeline *Timeline) Color(elapsed float64) RGBA {
+	from, to, progress := timeline.locate(elapsed)
+	mix := func(a, b uint8) uint8 {
+		return uint8(math.Max(0, math.Min(255, float64(a)+(float64(b)-float64(a))*progress+0.5)))
+	}
+	return RGBA{mix(from.Color.R, to.Color.R), mix(from.Color.G, to.Color.G), mix(from.Color.B, to.Color.B), mix(from.Color.A, to.Color.A)}
+}
+
+// locate the keyframes either side of elapsed and the eased progress between them
+func (timeline *Timeline) locate(elapsed float64) (from Keyframe, to Keyframe, progress float64) {
+	if len(timeline.keyframes) == 0 {
+		return Keyframe{}, Keyframe{}, 0
+	}
+
+	duration := timeline.Duration()
+	if duration <= 0 {
+		last := timeline.keyframes[len(timeline.keyframes)-1]
+		return last, last, 0
+	}
+
+	// stop on the last frame of the final pass
+	pass := math.Floor(elapsed / duration)
+	local := elapsed - pass*duration
+	if timeline.Finished(elapsed) {
+		pass = float64(timeline.repeat - 1)
+		local = duration
+	}
+	if timeline.pingPong && int(pass)%2 == 1 {
+		local = duration - local
+	}
+
+	for i := 1; i < len(timeline.keyframes); i++ {
+		to = timeline.keyframes[i]
+		if local <= to.Time || i == len(timeline.keyframes)-1 {
+			from = timeline.keyframes[i-1]
+			if to.Time <= from.Time {
+				return from, to, 1
+			}
+
+			easing := to.Easing
+			if easing == nil {
+				easing = Linear
+			}
+			return from, to, easing(math.Max(0, math.Min(1, (local-from.Time)/(to.Time-from.Time))))
+		}
+	}
+
+	// a single keyframe holds its value
+	return timeline.keyframes[0], timeline.keyframes[0], 0
+}
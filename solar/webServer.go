@@ -0,0 +1,290 @@
+package solar
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// LaunchWebServer serves the pages and api of the wall on addr, blocks until the server fails
+// Displays may register their own handlers on http.DefaultServeMux before this is called
+func LaunchWebServer(solarSystem *System, addr string) {
+
+	http.HandleFunc("/layout.svg", func(w http.ResponseWriter, r *http.Request) { layoutSvgHandler(solarSystem, w, r) })
+	http.HandleFunc("/api/brightness", func(w http.ResponseWriter, r *http.Request) { brightnessHandler(solarSystem, w, r) })
+	http.HandleFunc("/api/scene", func(w http.ResponseWriter, r *http.Request) { sceneHandler(solarSystem, w, r) })
+	http.HandleFunc("/api/moon", func(w http.ResponseWriter, r *http.Request) { moonHandler(solarSystem, w, r) })
+	http.HandleFunc("/api/events", func(w http.ResponseWriter, r *http.Request) { eventsHandler(solarSystem, w, r) })
+	http.HandleFunc("/api/countdown", func(w http.ResponseWriter, r *http.Request) { countdownHandler(solarSystem, w, r) })
+	http.HandleFunc("/api/notify", func(w http.ResponseWriter, r *http.Request) { notifyHandler(solarSystem, w, r) })
+
+	log.Print("Server listening on ", addr)
+	log.Fatal(http.ListenAndServe(addr, nil))
+}
+
+// layoutSvgHandler Return the layout of the wall, ?colored=1 fills the leds with the current frame
+func layoutSvgHandler(solarSystem *System, w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.Header().Set("Cache-control", "max-age=0, must-revalidate, no-store")
+
+	solarSystem.Lock()
+	defer solarSystem.Unlock()
+
+	WriteLayoutSVG(w, solarSystem, r.URL.Query().Get("colored") == "1")
+}
+
+// brightnessHandler Return the master brightness, POST level=0-100 and optionally ramp=seconds to change it
+func brightnessHandler(solarSystem *System, w http.ResponseWriter, r *http.Request) {
+	solarSystem.Lock()
+	defer solarSystem.Unlock()
+
+	brightness := solarSystem.Brightness()
+
+	if r.Method == http.MethodPost {
+		level, err := strconv.ParseFloat(r.FormValue("level"), 64)
+		if err != nil || math.IsNaN(level) || math.IsInf(level, 0) {
+			http.Error(w, "level must be a number from 0 to 100", http.StatusBadRequest)
+			return
+		}
+
+		ramp := 1.0
+		if rampText := r.FormValue("ramp"); rampText != "" {
+			ramp, err = strconv.ParseFloat(rampText, 64)
+			if err != nil || ramp < 0 || math.IsNaN(ramp) || math.IsInf(ramp, 0) {
+				http.Error(w, "ramp must be a number of seconds, 0 or more", http.StatusBadRequest)
+				return
+			}
+		}
+
+		brightness.Set(level, ramp, time.Now())
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(brightness.State())
+}
+
+// sceneState json returned by /api/scene
+type sceneState struct {
+	Scene  string   `json:"scene"`
+	Manual bool     `json:"manual"`
+	Scenes []string `json:"scenes"`
+}
+
+// sceneHandler Return the scene being shown, POST scene=name to show another until the schedule next changes
+func sceneHandler(solarSystem *System, w http.ResponseWriter, r *http.Request) {
+	solarSystem.Lock()
+	defer solarSystem.Unlock()
+
+	if r.Method == http.MethodPost {
+		if err := solarSystem.SetScene(r.FormValue("scene")); err != nil {
+			http.Error(w, err.Error(), sceneErrorStatus(err))
+			return
+		}
+		solarSystem.SceneSchedule().SetManual(time.Now())
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sceneState{
+		Scene:  solarSystem.SceneName(),
+		Manual: solarSystem.SceneSchedule().Manual(),
+		Scenes: SceneNames(),
+	})
+}
+
+// sceneErrorStatus http status for an error from SetScene, a conflict while the scene is held
+func sceneErrorStatus(err error) int {
+	if err == errSceneHeld {
+		return http.StatusConflict
+	}
+	return http.StatusBadRequest
+}
+
+// moonState json returned by /api/moon
+type moonState struct {
+	Phase       string    `json:"phase"`
+	Age         float64   `json:"age"`
+	Illuminated float64   `json:"illuminated"`
+	Time        time.Time `json:"time"`
+	Timescale   float64   `json:"timescale"`
+}
+
+// moonHandler Return the phase of the moon at the simulation time
+func moonHandler(solarSystem *System, w http.ResponseWriter, r *http.Request) {
+	solarSystem.Lock()
+	now := solarSystem.Clock().Now()
+	timescale := solarSystem.Clock().Timescale()
+	solarSystem.Unlock()
+
+	age, illuminated := MoonPhase(now)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(moonState{
+		Phase:       MoonPhaseName(age),
+		Age:         age,
+		Illuminated: illuminated,
+		Time:        now,
+		Timescale:   timescale,
+	})
+}
+
+// eventsHandler Return the sky events of the next ?days=30 days from the simulation time, up to a year
+func eventsHandler(solarSystem *System, w http.ResponseWriter, r *http.Request) {
+	days := 30
+	if daysText := r.FormValue("days"); daysText != "" {
+		var err error
+		days, err = strconv.Atoi(daysText)
+		if err != nil || days < 1 || days > 366 {
+			http.Error(w, "days must be a number from 1 to 366", http.StatusBadRequest)
+			return
+		}
+	}
+
+	// the time zone is loaded on first use, so look it up while no frame can be doing the same
+	solarSystem.Lock()
+	now := solarSystem.Clock().Now()
+	zone := solarSystem.location.TimeLocation()
+	solarSystem.Unlock()
+
+	events := solarSystem.SkyCalendar().Upcoming(now, days, zone)
+	if events == nil {
+		events = []SkyEvent{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(events)
+}
+
+// countdownHandler Return the countdown, POST action=start, pause or reset with an optional duration=5m to control it
+// Starting shows the countdown scene unless a countdown scene is already showing
+func countdownHandler(solarSystem *System, w http.ResponseWriter, r *http.Request) {
+	solarSystem.Lock()
+	defer solarSystem.Unlock()
+
+	now := time.Now()
+	countdown := solarSystem.Countdown()
+
+	if r.Method == http.MethodPost {
+		var duration time.Duration
+		if durationText := r.FormValue("duration"); durationText != "" {
+			var err error
+			duration, err = time.ParseDuration(durationText)
+			if err != nil || duration <= 0 {
+				http.Error(w, "duration must be a positive duration such as 90s or 5m", http.StatusBadRequest)
+				return
+			}
+		}
+
+		switch r.FormValue("action") {
+		case "start":
+			if scene := solarSystem.SceneName(); scene != "countdown" && scene != "timer" {
+				if err := solarSystem.SetScene("countdown"); err != nil {
+					http.Error(w, err.Error(), sceneErrorStatus(err))
+					return
+				}
+				solarSystem.SceneSchedule().SetManual(now)
+			}
+			if duration > 0 {
+				countdown.Reset(duration)
+			}
+			countdown.Start(now)
+		case "pause":
+			countdown.Pause(now)
+		case "reset":
+			countdown.Reset(duration)
+		default:
+			http.Error(w, "action must be start, pause or reset", http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(countdown.State(now))
+}
+
+// notifyHandler Return the notifications, POST color, pattern, duration, priority, planet and expire to add one, DELETE clears them all
+func notifyHandler(solarSystem *System, w http.ResponseWriter, r *http.Request) {
+	solarSystem.Lock()
+	defer solarSystem.Unlock()
+
+	now := time.Now()
+	notifications := solarSystem.Notifications()
+
+	switch r.Method {
+	case http.MethodPost:
+		notification, err := parseNotification(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := notifications.Add(notification, now, solarSystem); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+	case http.MethodDelete:
+		notifications.Clear()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(notifications.State())
+}
+
+// parseNotification read a notification from the form values of r, anything not given uses a default
+func parseNotification(r *http.Request) (Notification, error) {
+	notification := Notification{
+		Color:    RGBA{R: 255, G: 255, B: 255, A: 255},
+		Pattern:  "pulse",
+		Duration: 5,
+		Expire:   60,
+	}
+
+	if colorText := r.FormValue("color"); colorText != "" {
+		color, err := ParseColor(colorText)
+		if err != nil {
+			return notification, err
+		}
+		notification.Color = color
+	}
+
+	if pattern := r.FormValue("pattern"); pattern != "" {
+		if !notificationPatterns[pattern] {
+			return notification, fmt.Errorf("pattern must be solid, pulse, flash or ripple")
+		}
+		notification.Pattern = pattern
+	}
+
+	for _, seconds := range []struct {
+		name  string
+		value *float64
+	}{{"duration", &notification.Duration}, {"expire", &notification.Expire}} {
+		if text := r.FormValue(seconds.name); text != "" {
+			duration, err := time.ParseDuration(text)
+			if err != nil || duration <= 0 {
+				return notification, fmt.Errorf("%s must be a positive duration such as 10s or 2m", seconds.name)
+			}
+			*seconds.value = duration.Seconds()
+		}
+	}
+
+	if priorityText := r.FormValue("priority"); priorityText != "" {
+		priority, err := strconv.Atoi(priorityText)
+		if err != nil {
+			return notification, fmt.Errorf("priority must be a whole number")
+		}
+		notification.Priority = priority
+	}
+
+	if planetName := r.FormValue("planet"); planetName != "" {
+		planet, ok := ParsePlanetIndex(planetName)
+		if !ok {
+			return notification, fmt.Errorf("unknown planet %q", planetName)
+		}
+		notification.Planet = planet
+		notification.PlanetIsSet = true
+	}
+
+	return notification, nil
+}
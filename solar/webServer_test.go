@@ -0,0 +1,114 @@
+package solar
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// post values to handler and return the status code
+func post(solarSystem *System, handler func(*System, http.ResponseWriter, *http.Request), values url.Values) int {
+	request := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(values.Encode()))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	recorder := httptest.NewRecorder()
+	handler(solarSystem, recorder, request)
+	return recorder.Code
+}
+
+func TestBrightnessHandlerRejectsNonFinite(t *testing.T) {
+	solarSystem := DefaultSystem()
+
+	for _, values := range []url.Values{
+		{"level": {"NaN"}},
+		{"level": {"Inf"}},
+		{"level": {"-Inf"}},
+		{"level": {"50"}, "ramp": {"NaN"}},
+		{"level": {"50"}, "ramp": {"+Inf"}},
+		{"level": {"50"}, "ramp": {"-1"}},
+	} {
+		if code := post(solarSystem, brightnessHandler, values); code != http.StatusBadRequest {
+			t.Errorf("%v returned %d, want 400", values, code)
+		}
+	}
+	if state := solarSystem.Brightness().State(); state.Target != 100 {
+		t.Errorf("target changed to %v by rejected requests", state.Target)
+	}
+
+	if code := post(solarSystem, brightnessHandler, url.Values{"level": {"50"}, "ramp": {"0"}}); code != http.StatusOK {
+		t.Errorf("level 50 returned %d", code)
+	}
+	if state := solarSystem.Brightness().State(); state.Target != 50 {
+		t.Errorf("target %v, want 50", state.Target)
+	}
+}
+
+func TestSceneHandlerConflictsWhileCalibrating(t *testing.T) {
+	solarSystem := DefaultSystem()
+	solarSystem.SceneSchedule().Hold()
+	solarSystem.ClearDrawables()
+	markers := NewCalibrationMarkers(Sun, solarSystem)
+	solarSystem.AddDrawable(markers)
+
+	if code := post(solarSystem, sceneHandler, url.Values{"scene": {"plasma"}}); code != http.StatusConflict {
+		t.Errorf("scene change returned %d, want 409", code)
+	}
+	if front := solarSystem.drawables.Front(); front == nil || front.Value != Drawable(markers) || solarSystem.drawables.Len() != 1 {
+		t.Error("calibration markers were replaced")
+	}
+}
+
+func TestCountdownStartConflictsWhileCalibrating(t *testing.T) {
+	solarSystem := DefaultSystem()
+	solarSystem.SceneSchedule().Hold()
+
+	if code := post(solarSystem, countdownHandler, url.Values{"action": {"start"}, "duration": {"2m"}}); code != http.StatusConflict {
+		t.Errorf("countdown start returned %d, want 409", code)
+	}
+	if solarSystem.Countdown().Running() || solarSystem.SceneName() == "countdown" {
+		t.Error("countdown started while the scene was held")
+	}
+}
+
+func TestCalibrationRejectsNonFiniteNudge(t *testing.T) {
+	solarSystem := DefaultSystem()
+	calibration := &Calibration{solarSystem: solarSystem, markers: NewCalibrationMarkers(Sun, solarSystem)}
+	before := solarSystem.planets[Sun].angleOffset
+
+	for _, nudge := range []string{"NaN", "Inf", "-Inf", "east"} {
+		request := httptest.NewRequest(http.MethodPost, "/api/calibrate", strings.NewReader(url.Values{"nudge": {nudge}}.Encode()))
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		recorder := httptest.NewRecorder()
+		calibration.apiHandler(recorder, request)
+		if recorder.Code != http.StatusBadRequest {
+			t.Errorf("nudge %s returned %d, want 400", nudge, recorder.Code)
+		}
+	}
+	if after := solarSystem.planets[Sun].angleOffset; after != before {
+		t.Errorf("angleOffset changed from %v to %v", before, after)
+	}
+}
+
+func TestEventsHandlerWhileAnimating(t *testing.T) {
+	solarSystem := DefaultSystem()
+	solarSystem.location = &Location{Latitude: 30.27, Longitude: -97.74, TimeZone: "America/Chicago"}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for frame := 0; frame < 20; frame++ {
+			solarSystem.Lock()
+			solarSystem.Animate(0.01)
+			solarSystem.Unlock()
+		}
+	}()
+
+	recorder := httptest.NewRecorder()
+	eventsHandler(solarSystem, recorder, httptest.NewRequest(http.MethodGet, "/api/events?days=7", nil))
+	<-done
+
+	if recorder.Code != http.StatusOK {
+		t.Errorf("events returned %d", recorder.Code)
+	}
+}
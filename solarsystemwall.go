@@ -1,6 +1,14 @@
 package main
 
 import (
+	"flag"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
 	"runtime"
 	"time"
 	"fmt"
@@ -8,17 +16,233 @@ import (
 	solar "github.com/brandonagr/solarsystemwall/solar"
 )
 
+var (
+	layoutPath  = flag.String("layout", "layout.json", "layout file describing this install, the built in layout is used if it does not exist")
+	listenAddr  = flag.String("listen", ":8080", "address the web server listens on")
+	displayType = flag.String("display", "default", "where to render: default (leds or web preview), adalight, terminal")
+	ttyPath     = flag.String("tty", "/dev/ttyACM0", "serial device of the adalight arduino")
+	baudRate    = flag.Int("baud", 115200, "baud rate of the adalight arduino")
+	columns     = flag.Int("columns", 136, "width in characters of the terminal display")
+
+	inputProtocol = flag.String("input", "none", "let a lighting console drive the wall: none, sacn, artnet")
+	firstUniverse = flag.Int("universe", 1, "dmx universe of the Sun, each following planet uses the next universe")
+	inputTimeout  = flag.Duration("input-timeout", 5*time.Second, "how long the lighting input can be silent before resuming animation")
+
+	mqttBroker          = flag.String("mqtt", "", "mqtt broker to show the wall to Home Assistant as a light, such as tcp://localhost:1883, none when empty")
+	mqttUsername        = flag.String("mqtt-user", "", "username for the mqtt broker")
+	mqttPassword        = flag.String("mqtt-password", "", "password for the mqtt broker")
+	mqttNodeID          = flag.String("mqtt-id", "solarsystemwall", "mqtt client id and topic prefix of the wall")
+	mqttDiscoveryPrefix = flag.String("mqtt-discovery", "homeassistant", "topic prefix Home Assistant watches for discovery")
+
+	timescale = flag.Float64("timescale", 1, "simulated seconds per real second for the moon, sun and planet rotation, such as 86400 for a day a second")
+)
+
 func main() {
+	flag.Parse()
+
 	system := solar.DefaultSystem()
-	display := solar.NewDisplay(system)
+	err := solar.LoadLayout(*layoutPath, system)
+	if err != nil && !os.IsNotExist(err) {
+		log.Fatal(err)
+	}
+	system.Clock().SetTimescale(*timescale)
+
+	switch flag.Arg(0) {
+	case "record":
+		record(system, flag.Args()[1:])
+		return
+	case "svg":
+		exportSvg(system, flag.Args()[1:])
+		return
+	case "brightness":
+		setBrightness(flag.Args()[1:])
+		return
+	case "countdown":
+		controlCountdown(flag.Args()[1:])
+		return
+	case "notify":
+		notify(flag.Args()[1:])
+		return
+	case "calibrate":
+		solar.NewCalibration(system, *layoutPath)
+		log.Print("Calibrate at http://localhost", *listenAddr, "/calibrate")
+	case "":
+	default:
+		log.Fatal("Unknown command ", flag.Arg(0))
+	}
+
+	display := newDisplay(system)
 	defer display.Dispose()
 
+	var input *solar.LightingInput
+	if *inputProtocol != "none" {
+		input, err = solar.NewLightingInput(system, *inputProtocol, *firstUniverse, *inputTimeout)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer input.Close()
+	}
+
+	if *mqttBroker != "" {
+		bridge, err := solar.NewMQTTBridge(system, *mqttBroker, *mqttUsername, *mqttPassword, *mqttNodeID, *mqttDiscoveryPrefix)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer bridge.Close()
+	}
+
+	go solar.LaunchWebServer(system, *listenAddr)
+
 	fmt.Println("Beginning Animation")
 
-	runAnimationLoopForever(system, display)
+	runAnimationLoopForever(system, display, input)
+}
+
+// newDisplay creates the display selected on the command line
+func newDisplay(system *solar.System) solar.Display {
+	switch *displayType {
+	case "adalight":
+		return solar.NewAdalightDisplay(system, *ttyPath, *baudRate)
+	case "terminal":
+		return solar.NewTerminalDisplay(system, *columns)
+	case "default":
+		return solar.NewDisplay(system)
+	}
+
+	log.Fatal("Unknown display ", *displayType)
+	return nil
+}
+
+// record runs the animation with a fixed timestep, writing each frame to a file instead of a display
+func record(system *solar.System, args []string) {
+	flags := flag.NewFlagSet("record", flag.ExitOnError)
+	seconds := flags.Float64("seconds", 10, "length of the recording in seconds")
+	fps := flags.Float64("fps", 30, "frames per second")
+	format := flags.String("format", "gif", "gif, apng, or png for a numbered png per frame")
+	out := flags.String("out", "recording.gif", "file to write, the prefix of each file for png")
+	width := flags.Int("width", 680, "width of the recording in pixels")
+	dotSize := flags.Float64("dot", 4, "diameter of each led in pixels")
+	flags.Parse(args)
+
+	display, err := solar.NewRecorderDisplay(system, *out, *format, *width, *dotSize, *fps)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer display.Dispose()
+
+	dt := 1.0 / *fps
+	frameCount := int(*seconds * *fps)
+	for frame := 0; frame < frameCount; frame++ {
+		system.Animate(dt)
+		display.Render(system)
+	}
+}
+
+// exportSvg writes the layout of the wall as an svg, optionally colored with the frame after animating for a while
+func exportSvg(system *solar.System, args []string) {
+	flags := flag.NewFlagSet("svg", flag.ExitOnError)
+	out := flags.String("out", "layout.svg", "file to write")
+	colored := flags.Bool("colored", false, "fill each led with its color in the current frame")
+	at := flags.Float64("at", 0, "seconds to animate before capturing the colored frame")
+	flags.Parse(args)
+
+	system.Animate(*at)
+
+	file, err := os.Create(*out)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+
+	if err := solar.WriteLayoutSVG(file, system, *colored); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// setBrightness prints the brightness of the running wall, or changes it to the percent given
+func setBrightness(args []string) {
+	flags := flag.NewFlagSet("brightness", flag.ExitOnError)
+	ramp := flags.Float64("ramp", 1, "seconds to fade to the new level")
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		callAPI("/api/brightness", nil)
+		return
+	}
+
+	callAPI("/api/brightness", url.Values{
+		"level": {flags.Arg(0)},
+		"ramp":  {strconv.FormatFloat(*ramp, 'f', -1, 64)},
+	})
+}
+
+// controlCountdown prints the countdown of the running wall, or starts, pauses or resets it with an optional duration
+func controlCountdown(args []string) {
+	if len(args) == 0 {
+		callAPI("/api/countdown", nil)
+		return
+	}
+
+	values := url.Values{"action": {args[0]}}
+	if len(args) > 1 {
+		values.Set("duration", args[1])
+	}
+	callAPI("/api/countdown", values)
+}
+
+// notify flashes a notification on the running wall, or prints the notifications when no color is given
+func notify(args []string) {
+	flags := flag.NewFlagSet("notify", flag.ExitOnError)
+	pattern := flags.String("pattern", "pulse", "solid, pulse, flash or ripple")
+	duration := flags.Duration("duration", 5*time.Second, "how long the notification shows")
+	priority := flags.Int("priority", 0, "a higher priority replaces a lower one that is showing")
+	planet := flags.String("planet", "", "planet to show on or ripple from, the whole wall when empty")
+	expire := flags.Duration("expire", time.Minute, "how long the notification may wait behind others before it is dropped")
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		callAPI("/api/notify", nil)
+		return
+	}
+
+	callAPI("/api/notify", url.Values{
+		"color":    {flags.Arg(0)},
+		"pattern":  {*pattern},
+		"duration": {duration.String()},
+		"priority": {strconv.Itoa(*priority)},
+		"planet":   {*planet},
+		"expire":   {expire.String()},
+	})
+}
+
+// callAPI sends values to the api of the wall running on -listen and prints the reply, a nil values is a GET
+func callAPI(path string, values url.Values) {
+	address := *listenAddr
+	if strings.HasPrefix(address, ":") {
+		address = "localhost" + address
+	}
+	apiURL := "http://" + address + path
+
+	var response *http.Response
+	var err error
+	if values == nil {
+		response, err = http.Get(apiURL)
+	} else {
+		response, err = http.PostForm(apiURL, values)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer response.Body.Close()
+
+	body, _ := ioutil.ReadAll(response.Body)
+	if response.StatusCode != http.StatusOK {
+		log.Fatal(response.Status, ": ", strings.TrimSpace(string(body)))
+	}
+	fmt.Print(string(body))
 }
 
-func runAnimationLoopForever(system *solar.System, display solar.Display) {
+func runAnimationLoopForever(system *solar.System, display solar.Display, input *solar.LightingInput) {
 	curTime := time.Now()
 	prevTime := curTime
 
@@ -34,7 +258,12 @@ func runAnimationLoopForever(system *solar.System, display solar.Display) {
 		prevTime, curTime = curTime, time.Now()
 		dt := curTime.Sub(prevTime).Seconds()
 
+		system.Lock()
 		system.Animate(dt)
+		if input != nil {
+			input.Apply(system)
+		}
 		display.Render(system)
+		system.Unlock()
 	}
 }